@@ -0,0 +1,44 @@
+package config
+
+import (
+	"os"
+	"rental-mobil/models"
+	"strconv"
+)
+
+// Aturan perhitungan insentif supir
+const (
+	IncentiveRulePercentage = "percentage" // persentase dari total biaya booking
+	IncentiveRuleDaily      = "daily"      // nominal tetap per hari sewa
+)
+
+// DriverIncentiveRule menyimpan aturan insentif supir yang aktif
+type DriverIncentiveRule struct {
+	Rule string
+	Rate float64
+}
+
+// GetDriverIncentiveRule membaca aturan insentif dari variabel lingkungan
+// DRIVER_INCENTIVE_RULE dan DRIVER_INCENTIVE_RATE. Jika tidak diisi,
+// insentif default adalah 10% dari total biaya booking.
+func GetDriverIncentiveRule() DriverIncentiveRule {
+	rule := DriverIncentiveRule{Rule: IncentiveRulePercentage, Rate: 10}
+
+	if r := os.Getenv("DRIVER_INCENTIVE_RULE"); r == IncentiveRulePercentage || r == IncentiveRuleDaily {
+		rule.Rule = r
+	}
+	if r, err := strconv.ParseFloat(os.Getenv("DRIVER_INCENTIVE_RATE"), 64); err == nil && r >= 0 {
+		rule.Rate = r
+	}
+
+	return rule
+}
+
+// Calculate menghitung insentif berdasarkan total biaya dan durasi sewa,
+// dibulatkan ke rupiah terdekat
+func (r DriverIncentiveRule) Calculate(totalCost models.Money, days int) models.Money {
+	if r.Rule == IncentiveRuleDaily {
+		return models.RoundMoney(r.Rate).Times(days)
+	}
+	return totalCost.Percent(r.Rate)
+}
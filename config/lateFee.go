@@ -0,0 +1,16 @@
+package config
+
+import (
+	"os"
+	"strconv"
+)
+
+// GetLateFeeMultiplier membaca pengali denda keterlambatan dari variabel
+// lingkungan LATE_FEE_MULTIPLIER. Default 1.5 kali tarif harian.
+func GetLateFeeMultiplier() float64 {
+	multiplier, err := strconv.ParseFloat(os.Getenv("LATE_FEE_MULTIPLIER"), 64)
+	if err != nil || multiplier < 0 {
+		return 1.5
+	}
+	return multiplier
+}
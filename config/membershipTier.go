@@ -0,0 +1,17 @@
+package config
+
+import (
+	"os"
+	"time"
+)
+
+// GetMembershipTierInterval membaca interval evaluasi tingkat membership dari
+// variabel lingkungan MEMBERSHIP_TIER_INTERVAL (contoh "24h"). Default 0, yaitu
+// evaluasi otomatis nonaktif sampai syarat setiap tingkat sudah diatur.
+func GetMembershipTierInterval() time.Duration {
+	interval, err := time.ParseDuration(os.Getenv("MEMBERSHIP_TIER_INTERVAL"))
+	if err != nil || interval < 0 {
+		return 0
+	}
+	return interval
+}
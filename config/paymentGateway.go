@@ -0,0 +1,33 @@
+package config
+
+import (
+	"os"
+	"strconv"
+)
+
+// GetPaymentGateway membaca nama payment gateway default dari variabel
+// lingkungan PAYMENT_GATEWAY. Kosong berarti provider wajib dipilih per tagihan.
+func GetPaymentGateway() string {
+	return os.Getenv("PAYMENT_GATEWAY")
+}
+
+// GetFakeGatewayEnabled membaca FAKE_GATEWAY_ENABLED. Gateway palsu hanya
+// untuk pengujian lokal dan harus diaktifkan secara eksplisit.
+func GetFakeGatewayEnabled() bool {
+	enabled, _ := strconv.ParseBool(os.Getenv("FAKE_GATEWAY_ENABLED"))
+	return enabled
+}
+
+// GetFakeGatewaySecret membaca secret penandatangan callback gateway palsu
+// dari FAKE_GATEWAY_SECRET. Tidak ada nilai default.
+func GetFakeGatewaySecret() string {
+	return os.Getenv("FAKE_GATEWAY_SECRET")
+}
+
+// GetBaseURL membaca URL publik aplikasi dari variabel lingkungan BASE_URL
+func GetBaseURL() string {
+	if url := os.Getenv("BASE_URL"); url != "" {
+		return url
+	}
+	return "http://localhost:5000"
+}
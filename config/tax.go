@@ -0,0 +1,16 @@
+package config
+
+import (
+	"os"
+	"strconv"
+)
+
+// GetTaxPercentage membaca persentase pajak dari variabel lingkungan
+// TAX_PERCENTAGE. Default 11 sesuai tarif PPN.
+func GetTaxPercentage() float64 {
+	tax, err := strconv.ParseFloat(os.Getenv("TAX_PERCENTAGE"), 64)
+	if err != nil || tax < 0 {
+		return 11
+	}
+	return tax
+}
@@ -2,9 +2,11 @@ package controllers
 
 import (
 	// "log"
+	"errors"
 	"net/http"
-	"rental-mobil/config"
 	"rental-mobil/models"
+	"rental-mobil/repository"
+	"rental-mobil/services"
 	"strconv"
 	"time"
 
@@ -12,7 +14,7 @@ import (
 )
 
 // GetAllBookings mengambil semua data booking
-func GetAllBookings(c echo.Context) error {
+func (h *Handler) GetAllBookings(c echo.Context) error {
 	// Get page and limit from query parameters, defaulting to page 1 and limit 5 if not provided
 	page := 1
 	limit := 5
@@ -34,24 +36,13 @@ func GetAllBookings(c echo.Context) error {
 	// Calculate offset for pagination
 	offset := (page - 1) * limit
 
-	var bookings []models.Booking
-	query := `SELECT id, customer_id, car_id, start_rent, end_rent, total_cost, finished FROM bookings 
-              LIMIT $1 OFFSET $2`
-	err := config.DB.Select(&bookings, query, limit, offset)
+	// Repository juga mengembalikan total booking untuk menghitung jumlah halaman
+	bookings, totalBookings, err := h.Repos.Bookings.List(limit, offset, includeDeleted(c))
 	if err != nil {
 		c.Logger().Error("Error fetching bookings:", err)
 		return c.JSON(http.StatusInternalServerError, map[string]string{"message": "Failed to fetch bookings"})
 	}
 
-	// Get the total number of bookings to calculate total pages
-	var totalBookings int
-	countQuery := `SELECT COUNT(*) FROM bookings`
-	err = config.DB.Get(&totalBookings, countQuery)
-	if err != nil {
-		c.Logger().Error("Error counting bookings:", err)
-		return c.JSON(http.StatusInternalServerError, map[string]string{"message": "Failed to count bookings"})
-	}
-
 	// Calculate the total number of pages
 	totalPages := (totalBookings + limit - 1) / limit
 
@@ -69,7 +60,7 @@ func GetAllBookings(c echo.Context) error {
 }
 
 // CreateBooking membuat data booking baru
-func CreateBooking(c echo.Context) error {
+func (h *Handler) CreateBooking(c echo.Context) error {
     booking := new(models.Booking)
 
     // Bind the request data into the booking struct
@@ -77,87 +68,37 @@ func CreateBooking(c echo.Context) error {
         return c.JSON(http.StatusBadRequest, map[string]string{"message": "Invalid input"})
     }
 
-    // Validasi customer_id dan car_id
-    if booking.CustomerID <= 0 {
-        return c.JSON(http.StatusBadRequest, map[string]string{"message": "Invalid customer ID"})
-    }
-    if booking.CarID <= 0 {
-        return c.JSON(http.StatusBadRequest, map[string]string{"message": "Invalid car ID"})
+    // Validasi customer, mobil, jenis booking dan supir yang dirujuk
+    if ok, err := checkBookingReferences(c, h.Repos, booking); !ok {
+        return err
     }
 
-    // Validasi start_rent dan end_rent
-    if booking.StartRent > booking.EndRent {
-        return c.JSON(http.StatusBadRequest, map[string]string{"message": "Start rent must be before end rent"})
-    }
-
-    // Ambil data membership berdasarkan customer_id
-    var membership models.Membership
-    query := `
-        SELECT m.id, m.name, m.discount
-        FROM membership m
-        JOIN customers c ON c.membership_id = m.id
-        WHERE c.id = $1
-    `
-    err := config.DB.Get(&membership, query, booking.CustomerID)
+    // Hitung harga dengan aturan yang sama seperti UpdateBooking
+    price, err := services.CalculateBookingPrice(h.Repos, booking)
     if err != nil {
-        return c.JSON(http.StatusInternalServerError, map[string]string{"message": "Failed to fetch membership data"})
+        return pricingErrorResponse(c, err)
     }
 
-    // Ambil data mobil berdasarkan car_id
-    var car models.Car
-    carQuery := `SELECT id, daily_rent FROM cars WHERE id = $1`
-    err = config.DB.Get(&car, carQuery, booking.CarID)
-    if err != nil {
-        return c.JSON(http.StatusInternalServerError, map[string]string{"message": "Failed to fetch car data"})
+    // Repository mengunci mobil dan supir, menyimpan booking beserta
+    // invoicenya dalam satu transaksi
+    if err := h.Repos.Bookings.Create(booking, price); err != nil {
+        return bookingRepositoryErrorResponse(c, err, "Failed to create booking")
     }
 
-    // Mengonversi tanggal mulai dan selesai sewa ke tipe time.Time
-    startRentTime, err := time.Parse("2006-01-02", booking.StartRent)
-    if err != nil {
-        return c.JSON(http.StatusBadRequest, map[string]string{"message": "Invalid start rent date format"})
-    }
-    endRentTime, err := time.Parse("2006-01-02", booking.EndRent)
-    if err != nil {
-        return c.JSON(http.StatusBadRequest, map[string]string{"message": "Invalid end rent date format"})
-    }
-
-    // Hitung durasi sewa (dalam hari)
-    duration := int(endRentTime.Sub(startRentTime).Hours() / 24)
-
-    // Validasi durasi sewa
-    if duration <= 0 {
-        return c.JSON(http.StatusBadRequest, map[string]string{"message": "End rent date must be after start rent date"})
-    }
-
-    // Hitung total biaya sewa
-    booking.TotalCost = car.DailyRent * float64(duration) * (1 - membership.Discount/100)
-
-    // Ambil data driver berdasarkan driver_id
-    var driver models.Driver
-    driverQuery := `SELECT id, daily_cost FROM driver WHERE id = $1`
-    err = config.DB.Get(&driver, driverQuery, booking.DriverID)
-    if err != nil {
-        return c.JSON(http.StatusInternalServerError, map[string]string{"message": "Failed to fetch driver data"})
-    }
-
-    // Hitung biaya supir
-    booking.TotalDriverCost = driver.DailyCost * float64(duration)
-
-    // Simpan booking ke database
-    insertQuery := `INSERT INTO bookings (customer_id, car_id, start_rent, end_rent, total_cost, finished, discount, booking_type_id, driver_id, total_driver_cost) 
-        VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)`
-    _, err = config.DB.Exec(insertQuery, booking.CustomerID, booking.CarID, booking.StartRent, booking.EndRent, booking.TotalCost, booking.Finished, membership.Discount, booking.BookingTypeID, booking.DriverID, booking.TotalDriverCost)
-    if err != nil {
-        return c.JSON(http.StatusInternalServerError, map[string]string{"message": "Failed to create booking"})
-    }
-
-    return c.JSON(http.StatusCreated, map[string]string{"message": "Booking created successfully"})
+    return c.JSON(http.StatusCreated, map[string]interface{}{
+        "message": "Booking created successfully",
+        "id":      booking.ID,
+        "price":   price,
+    })
 }
 
 
 // UpdateBooking memperbarui data booking
-func UpdateBooking(c echo.Context) error {
-	id := c.Param("id")
+func (h *Handler) UpdateBooking(c echo.Context) error {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"message": "Invalid booking ID"})
+	}
 	booking := new(models.Booking)
 
 	// Bind data dari request body
@@ -165,71 +106,165 @@ func UpdateBooking(c echo.Context) error {
 		return c.JSON(http.StatusBadRequest, map[string]string{"message": "Invalid input"})
 	}
 
-	// Konversi tanggal string ke time.Time
-	startRent, err := time.Parse("2006-01-02", booking.StartRent)
-	if err != nil {
-		return c.JSON(http.StatusBadRequest, map[string]string{"message": "Invalid start date format"})
+	// Validasi customer, mobil, jenis booking dan supir yang dirujuk
+	if ok, err := checkBookingReferences(c, h.Repos, booking); !ok {
+		return err
 	}
 
-	endRent, err := time.Parse("2006-01-02", booking.EndRent)
+	// Hitung ulang harga dengan aturan yang sama seperti CreateBooking
+	price, err := services.CalculateBookingPrice(h.Repos, booking)
 	if err != nil {
-		return c.JSON(http.StatusBadRequest, map[string]string{"message": "Invalid end date format"})
+		return pricingErrorResponse(c, err)
+	}
+
+	// Booking yang sudah selesai atau dibatalkan tidak bisa diubah lagi;
+	// repository juga mengunci mobil dan supir lalu menyesuaikan invoice
+	booking.ID = id
+	if err := h.Repos.Bookings.Update(booking, price); err != nil {
+		return bookingRepositoryErrorResponse(c, err, "Failed to update booking")
+	}
+
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"message": "Booking updated successfully",
+		"price":   price,
+	})
+}
+
+// QuoteBooking menghitung rincian harga dan ketersediaan untuk payload yang
+// sama dengan CreateBooking tanpa menyimpan apapun ke database
+func (h *Handler) QuoteBooking(c echo.Context) error {
+	booking := new(models.Booking)
+	if err := c.Bind(booking); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"message": "Invalid input"})
 	}
 
-	// Validasi bahwa startRent tidak setelah endRent
-	if startRent.After(endRent) {
-		return c.JSON(http.StatusBadRequest, map[string]string{"message": "Start rent must be before end rent"})
+	// Validasi customer, mobil, jenis booking dan supir yang dirujuk
+	if ok, err := checkBookingReferences(c, h.Repos, booking); !ok {
+		return err
 	}
 
-	// Periksa apakah booking dengan ID tersebut ada
-	var existingBooking models.Booking
-	checkQuery := `SELECT id FROM bookings WHERE id = $1`
-	err = config.DB.Get(&existingBooking, checkQuery, id)
+	price, err := services.CalculateBookingPrice(h.Repos, booking)
 	if err != nil {
-		return c.JSON(http.StatusNotFound, map[string]string{"message": "Booking not found"})
+		return pricingErrorResponse(c, err)
 	}
 
-	// Ambil harga sewa harian mobil
-	var dailyRent float64
-	query := `SELECT daily_rent FROM cars WHERE id = $1`
-	err = config.DB.Get(&dailyRent, query, booking.CarID)
+	// Cek ketersediaan mobil dengan logika irisan yang sama seperti booking
+	car, err := h.Repos.Cars.Get(booking.CarID)
 	if err != nil {
 		return c.JSON(http.StatusInternalServerError, map[string]string{"message": "Failed to fetch car data"})
 	}
+	booked, err := h.Repos.Bookings.CountOverlapping(booking.CarID, booking.StartRent, booking.EndRent)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"message": "Failed to check car availability"})
+	}
+	carsAvailable := car.Stock - booked
+	if carsAvailable < 0 {
+		carsAvailable = 0
+	}
 
-	// Hitung durasi dan total biaya
-	duration := endRent.Sub(startRent).Hours() / 24
-	if duration < 1 {
-		return c.JSON(http.StatusBadRequest, map[string]string{"message": "End date must be at least one day after start date"})
+	driverAvailable := true
+	if booking.DriverID != nil {
+		assigned, err := h.Repos.Bookings.CountDriverOverlapping(*booking.DriverID, booking.StartRent, booking.EndRent)
+		if err != nil {
+			return c.JSON(http.StatusInternalServerError, map[string]string{"message": "Failed to check driver availability"})
+		}
+		driverAvailable = assigned == 0
 	}
-	booking.TotalCost = dailyRent * duration
 
-	// Update data booking di database
-	updateQuery := `
-        UPDATE bookings 
-        SET customer_id=$1, car_id=$2, start_rent=$3, end_rent=$4, total_cost=$5, finished=$6 
-        WHERE id=$7`
-	_, err = config.DB.Exec(updateQuery, booking.CustomerID, booking.CarID, booking.StartRent, booking.EndRent, booking.TotalCost, booking.Finished, id)
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"price":            price,
+		"available":        carsAvailable > 0 && driverAvailable,
+		"cars_available":   carsAvailable,
+		"driver_available": driverAvailable,
+	})
+}
+
+// pricingErrorResponse memetakan kesalahan dari services.CalculateBookingPrice
+// ke respons HTTP
+func pricingErrorResponse(c echo.Context, err error) error {
+	if pricingErr, ok := err.(services.PricingError); ok {
+		return c.JSON(http.StatusBadRequest, map[string]string{"message": pricingErr.Error()})
+	}
+	return c.JSON(http.StatusInternalServerError, map[string]string{"message": "Failed to calculate booking price"})
+}
+
+// DeleteBooking menghapus data booking secara soft delete agar riwayat dan
+// pembayarannya tetap tersimpan. Booking yang masih reserved atau picked_up
+// harus dibatalkan atau dikembalikan lebih dulu.
+func (h *Handler) DeleteBooking(c echo.Context) error {
+	id, err := strconv.Atoi(c.Param("id"))
 	if err != nil {
-		return c.JSON(http.StatusInternalServerError, map[string]string{"message": "Failed to update booking"})
+		return c.JSON(http.StatusBadRequest, map[string]string{"message": "Invalid booking ID"})
+	}
+
+	err = h.Repos.Bookings.Delete(id)
+	switch {
+	case errors.Is(err, repository.ErrNotFound):
+		return c.JSON(http.StatusNotFound, map[string]string{"message": "Booking not found"})
+	case errors.Is(err, repository.ErrBookingActive):
+		return c.JSON(http.StatusConflict, map[string]string{"message": "Booking is still active; cancel or return it first"})
+	case err != nil:
+		return c.JSON(http.StatusInternalServerError, map[string]string{"message": "Failed to delete booking"})
 	}
 
-	return c.JSON(http.StatusOK, map[string]string{"message": "Booking updated successfully"})
+	return c.JSON(http.StatusOK, map[string]string{"message": "Booking deleted successfully"})
 }
 
-// DeleteBooking menghapus data booking
-func DeleteBooking(c echo.Context) error {
-	id := c.Param("id")
-	query := `DELETE FROM bookings WHERE id=$1`
-	result, err := config.DB.Exec(query, id)
+// RestoreBooking mengembalikan booking yang sudah di-soft delete. Booking
+// aktif hanya bisa dipulihkan jika stok mobil dan supirnya masih tersedia
+func (h *Handler) RestoreBooking(c echo.Context) error {
+	id, err := strconv.Atoi(c.Param("id"))
 	if err != nil {
-		return c.JSON(http.StatusInternalServerError, map[string]string{"message": "Failed to delete booking"})
+		return c.JSON(http.StatusBadRequest, map[string]string{"message": "Invalid booking ID"})
+	}
+
+	err = h.Repos.Bookings.Restore(id)
+	if errors.Is(err, repository.ErrNotFound) {
+		return c.JSON(http.StatusNotFound, map[string]string{"message": "Deleted booking not found"})
+	}
+	if err != nil {
+		return bookingRepositoryErrorResponse(c, err, "Failed to restore booking")
 	}
 
-	rowsAffected, _ := result.RowsAffected()
-	if rowsAffected == 0 {
+	return c.JSON(http.StatusOK, map[string]string{"message": "Booking restored successfully"})
+}
+
+// bookingRepositoryErrorResponse memetakan kesalahan BookingRepository ke
+// respons HTTP; kesalahan lain dijawab 500 dengan failMessage
+func bookingRepositoryErrorResponse(c echo.Context, err error, failMessage string) error {
+	switch {
+	case errors.Is(err, repository.ErrNotFound):
 		return c.JSON(http.StatusNotFound, map[string]string{"message": "Booking not found"})
+	case errors.Is(err, repository.ErrBookingNotActive):
+		return c.JSON(http.StatusConflict, map[string]string{"message": "Booking can no longer be modified"})
+	case errors.Is(err, repository.ErrCarFullyBooked):
+		return c.JSON(http.StatusConflict, map[string]string{"message": "Car is fully booked for the selected dates"})
+	case errors.Is(err, repository.ErrDriverUnavailable):
+		return c.JSON(http.StatusConflict, map[string]string{"message": "Driver is already assigned for the selected dates"})
 	}
+	return c.JSON(http.StatusInternalServerError, map[string]string{"message": failMessage})
+}
 
-	return c.JSON(http.StatusOK, map[string]string{"message": "Booking deleted successfully"})
+// includeDeleted membaca query param include_deleted yang dipakai admin
+// untuk ikut menampilkan data yang sudah dihapus
+func includeDeleted(c echo.Context) bool {
+	include, _ := strconv.ParseBool(c.QueryParam("include_deleted"))
+	return include
+}
+
+// parseDateRange membaca query param from dan to dengan format YYYY-MM-DD
+// dan memastikan from tidak setelah to.
+func parseDateRange(c echo.Context) (time.Time, time.Time, string) {
+	from, err := time.Parse("2006-01-02", c.QueryParam("from"))
+	if err != nil {
+		return time.Time{}, time.Time{}, "Invalid from date format"
+	}
+	to, err := time.Parse("2006-01-02", c.QueryParam("to"))
+	if err != nil {
+		return time.Time{}, time.Time{}, "Invalid to date format"
+	}
+	if from.After(to) {
+		return time.Time{}, time.Time{}, "From date must not be after to date"
+	}
+	return from, to, ""
 }
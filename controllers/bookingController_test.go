@@ -0,0 +1,123 @@
+package controllers_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"rental-mobil/controllers"
+	"rental-mobil/models"
+	"rental-mobil/repository"
+	"strings"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+)
+
+// newMemoryHandler menyiapkan handler dengan repository in-memory berisi satu
+// mobil, satu pelanggan bermembership dan jenis booking lepas kunci
+func newMemoryHandler(t *testing.T) (*controllers.Handler, *repository.Repositories) {
+	t.Helper()
+
+	repos := repository.NewMemory()
+	membership := &models.Membership{Name: "Gold", Discount: 10}
+	if err := repos.Memberships.Create(membership); err != nil {
+		t.Fatal(err)
+	}
+	if err := repos.Customers.Create(&models.Customer{Name: "Budi", NIK: "3201", Phone: "0812", MembershipID: &membership.ID}); err != nil {
+		t.Fatal(err)
+	}
+	if err := repos.Cars.Create(&models.Car{Name: "Avanza", Stock: 1, DailyRent: 300000}); err != nil {
+		t.Fatal(err)
+	}
+	if err := repos.BookingTypes.Create(&models.BookingType{Name: "Car Only"}); err != nil {
+		t.Fatal(err)
+	}
+
+	return controllers.NewHandler(repos, nil), repos
+}
+
+// postBooking memanggil CreateBooking dengan body JSON
+func postBooking(t *testing.T, h *controllers.Handler, body string) *httptest.ResponseRecorder {
+	t.Helper()
+
+	req := httptest.NewRequest(http.MethodPost, "/bookings", strings.NewReader(body))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	rec := httptest.NewRecorder()
+	if err := h.CreateBooking(echo.New().NewContext(req, rec)); err != nil {
+		t.Fatal(err)
+	}
+	return rec
+}
+
+func TestCreateBookingWithMemoryRepositories(t *testing.T) {
+	t.Setenv("TAX_PERCENTAGE", "0")
+	h, repos := newMemoryHandler(t)
+
+	rec := postBooking(t, h, `{"customer_id": 1, "car_id": 1, "booking_type_id": 1, "start_rent": "2026-01-01", "end_rent": "2026-01-04"}`)
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("expected 201, got %d: %s", rec.Code, rec.Body.String())
+	}
+
+	bookings, total, err := repos.Bookings.List(10, 0, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if total != 1 {
+		t.Fatalf("expected 1 booking, got %d", total)
+	}
+	booking := bookings[0]
+	if booking.Status != models.BookingStatusReserved {
+		t.Errorf("expected status %q, got %q", models.BookingStatusReserved, booking.Status)
+	}
+	// 3 hari x 300.000 dengan diskon membership 10%
+	if booking.TotalCost != 810000 {
+		t.Errorf("expected total cost 810000, got %d", booking.TotalCost)
+	}
+
+	// Stok mobil hanya 1 sehingga booking yang beririsan ditolak
+	rec = postBooking(t, h, `{"customer_id": 1, "car_id": 1, "booking_type_id": 1, "start_rent": "2026-01-03", "end_rent": "2026-01-05"}`)
+	if rec.Code != http.StatusConflict {
+		t.Fatalf("expected 409 for overlapping booking, got %d: %s", rec.Code, rec.Body.String())
+	}
+}
+
+func TestCreateBookingRejectsUnknownReferences(t *testing.T) {
+	h, _ := newMemoryHandler(t)
+
+	rec := postBooking(t, h, `{"customer_id": 9, "car_id": 1, "booking_type_id": 1, "start_rent": "2026-01-01", "end_rent": "2026-01-04"}`)
+	if rec.Code != http.StatusUnprocessableEntity {
+		t.Fatalf("expected 422, got %d: %s", rec.Code, rec.Body.String())
+	}
+
+	var response struct {
+		Errors []controllers.FieldError `json:"errors"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &response); err != nil {
+		t.Fatal(err)
+	}
+	if len(response.Errors) != 1 || response.Errors[0].Field != "customer_id" {
+		t.Errorf("expected a single customer_id error, got %+v", response.Errors)
+	}
+}
+
+func TestDeleteBookingRejectsActiveBooking(t *testing.T) {
+	t.Setenv("TAX_PERCENTAGE", "0")
+	h, _ := newMemoryHandler(t)
+
+	rec := postBooking(t, h, `{"customer_id": 1, "car_id": 1, "booking_type_id": 1, "start_rent": "2026-01-01", "end_rent": "2026-01-04"}`)
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("expected 201, got %d: %s", rec.Code, rec.Body.String())
+	}
+
+	req := httptest.NewRequest(http.MethodDelete, "/bookings/1", nil)
+	rec = httptest.NewRecorder()
+	c := echo.New().NewContext(req, rec)
+	c.SetParamNames("id")
+	c.SetParamValues("1")
+	if err := h.DeleteBooking(c); err != nil {
+		t.Fatal(err)
+	}
+	if rec.Code != http.StatusConflict {
+		t.Fatalf("expected 409 for reserved booking, got %d: %s", rec.Code, rec.Body.String())
+	}
+}
@@ -0,0 +1,128 @@
+package controllers
+
+import (
+	"errors"
+	"net/http"
+	"rental-mobil/models"
+	"rental-mobil/repository"
+	"strconv"
+	"time"
+
+	"github.com/labstack/echo/v4"
+)
+
+// bookingTransitionResponse memetakan hasil perpindahan status booking dari
+// repository ke respons HTTP beserta sisa tagihannya
+func (h *Handler) bookingTransitionResponse(c echo.Context, booking *models.Booking, err error) error {
+	var transitionErr *repository.TransitionError
+	var depositErr *repository.DepositRequiredError
+	var balanceErr *repository.OutstandingBalanceError
+	switch {
+	case errors.Is(err, repository.ErrNotFound):
+		return c.JSON(http.StatusNotFound, map[string]string{"message": "Booking not found"})
+	case errors.As(err, &transitionErr):
+		return c.JSON(http.StatusConflict, map[string]string{"message": "Cannot change booking status from " + transitionErr.From + " to " + transitionErr.To})
+	case errors.As(err, &depositErr):
+		return c.JSON(http.StatusConflict, map[string]string{"message": "Security deposit of " + depositErr.Required.String() + " must be held before pickup"})
+	case errors.As(err, &balanceErr):
+		return c.JSON(http.StatusConflict, map[string]string{"message": "Booking has an outstanding balance of " + balanceErr.BalanceDue.String()})
+	case err != nil:
+		return c.JSON(http.StatusInternalServerError, map[string]string{"message": "Failed to update booking status"})
+	}
+
+	balance, err := h.Repos.Bookings.Balance(booking.ID)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"message": "Failed to calculate balance"})
+	}
+
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"message":          "Booking status updated to " + booking.Status,
+		"id":               booking.ID,
+		"status":           booking.Status,
+		"late_fee":         booking.LateFee,
+		"cancellation_fee": booking.CancellationFee,
+		"balance":          balance,
+	})
+}
+
+// PickupBooking menandai mobil sudah diambil pelanggan. Mobil dengan uang
+// jaminan hanya bisa diambil setelah uang jaminan ditahan.
+func (h *Handler) PickupBooking(c echo.Context) error {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"message": "Invalid booking ID"})
+	}
+
+	booking, err := h.Repos.Bookings.Pickup(id)
+	return h.bookingTransitionResponse(c, booking, err)
+}
+
+// ReturnBooking menandai mobil sudah dikembalikan, menghitung denda
+// keterlambatan dan mencatat insentif supir. Booking yang masih memiliki sisa
+// tagihan ditolak kecuali override_balance bernilai true.
+func (h *Handler) ReturnBooking(c echo.Context) error {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"message": "Invalid booking ID"})
+	}
+
+	var input struct {
+		ReturnDate      string `json:"return_date"`
+		OverrideBalance bool   `json:"override_balance"`
+	}
+	if err := c.Bind(&input); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"message": "Invalid input"})
+	}
+
+	// Tanggal pengembalian default adalah hari ini
+	returnDate := time.Now().Format("2006-01-02")
+	if input.ReturnDate != "" {
+		if _, err := time.Parse("2006-01-02", input.ReturnDate); err != nil {
+			return c.JSON(http.StatusBadRequest, map[string]string{"message": "Invalid return date format"})
+		}
+		returnDate = input.ReturnDate
+	}
+
+	booking, err := h.Repos.Bookings.Return(id, returnDate, input.OverrideBalance)
+	return h.bookingTransitionResponse(c, booking, err)
+}
+
+// CancelBooking membatalkan booking yang belum diambil tanpa menghapus
+// datanya. Biaya pembatalan dihitung dari kebijakan refund jenis booking
+// berdasarkan jarak hari ke start_rent, dan cancellation_fee pada respons
+// sudah termasuk pajak. Jika refund_method diisi, kelebihan pembayaran
+// langsung dicatat sebagai refund.
+func (h *Handler) CancelBooking(c echo.Context) error {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"message": "Invalid booking ID"})
+	}
+
+	var input struct {
+		Reason       string `json:"reason"`
+		RefundMethod string `json:"refund_method"`
+	}
+	if err := c.Bind(&input); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"message": "Invalid input"})
+	}
+	if input.Reason == "" {
+		return c.JSON(http.StatusBadRequest, map[string]string{"message": "Cancellation reason is required"})
+	}
+	if input.RefundMethod != "" && !paymentMethods[input.RefundMethod] {
+		return c.JSON(http.StatusBadRequest, map[string]string{"message": "Refund method must be cash, bank_transfer or qris"})
+	}
+
+	booking, err := h.Repos.Bookings.Cancel(id, input.Reason, input.RefundMethod)
+	return h.bookingTransitionResponse(c, booking, err)
+}
+
+// NoShowBooking menandai pelanggan tidak datang mengambil mobil
+func (h *Handler) NoShowBooking(c echo.Context) error {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"message": "Invalid booking ID"})
+	}
+
+	booking, err := h.Repos.Bookings.NoShow(id)
+	return h.bookingTransitionResponse(c, booking, err)
+}
@@ -0,0 +1,115 @@
+package controllers_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"rental-mobil/controllers"
+	"rental-mobil/models"
+	"strings"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+)
+
+// callStatus memanggil handler perpindahan status untuk booking id dengan body JSON
+func callStatus(t *testing.T, handler echo.HandlerFunc, id, body string) *httptest.ResponseRecorder {
+	t.Helper()
+
+	req := httptest.NewRequest(http.MethodPost, "/bookings/"+id, strings.NewReader(body))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	rec := httptest.NewRecorder()
+	c := echo.New().NewContext(req, rec)
+	c.SetParamNames("id")
+	c.SetParamValues(id)
+	if err := handler(c); err != nil {
+		t.Fatal(err)
+	}
+	return rec
+}
+
+// transitionResult adalah isi respons perpindahan status booking
+type transitionResult struct {
+	Status          string       `json:"status"`
+	LateFee         models.Money `json:"late_fee"`
+	CancellationFee models.Money `json:"cancellation_fee"`
+	Balance         struct {
+		BalanceDue models.Money `json:"balance_due"`
+	} `json:"balance"`
+}
+
+func decodeTransition(t *testing.T, rec *httptest.ResponseRecorder) transitionResult {
+	t.Helper()
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+	var result transitionResult
+	if err := json.Unmarshal(rec.Body.Bytes(), &result); err != nil {
+		t.Fatal(err)
+	}
+	return result
+}
+
+func newReservedBooking(t *testing.T) *controllers.Handler {
+	t.Helper()
+	t.Setenv("TAX_PERCENTAGE", "0")
+	t.Setenv("LATE_FEE_MULTIPLIER", "1.5")
+
+	h, _ := newMemoryHandler(t)
+	rec := postBooking(t, h, `{"customer_id": 1, "car_id": 1, "booking_type_id": 1, "start_rent": "2026-01-01", "end_rent": "2026-01-04"}`)
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("expected 201, got %d: %s", rec.Code, rec.Body.String())
+	}
+	return h
+}
+
+func TestReturnBookingRecordsLateFee(t *testing.T) {
+	h := newReservedBooking(t)
+
+	if result := decodeTransition(t, callStatus(t, h.PickupBooking, "1", `{}`)); result.Status != models.BookingStatusPickedUp {
+		t.Fatalf("expected status %q, got %q", models.BookingStatusPickedUp, result.Status)
+	}
+
+	// Belum dibayar sehingga pengembalian ditolak tanpa override
+	rec := callStatus(t, h.ReturnBooking, "1", `{"return_date": "2026-01-06"}`)
+	if rec.Code != http.StatusConflict {
+		t.Fatalf("expected 409 for outstanding balance, got %d: %s", rec.Code, rec.Body.String())
+	}
+
+	result := decodeTransition(t, callStatus(t, h.ReturnBooking, "1", `{"return_date": "2026-01-06", "override_balance": true}`))
+	// Terlambat 2 hari x 300.000 x 1,5
+	if result.LateFee != 900000 {
+		t.Errorf("expected late fee 900000, got %d", result.LateFee)
+	}
+	if result.Balance.BalanceDue != 810000+900000 {
+		t.Errorf("expected balance due 1710000, got %d", result.Balance.BalanceDue)
+	}
+
+	rec = callStatus(t, h.CancelBooking, "1", `{"reason": "Berubah pikiran"}`)
+	if rec.Code != http.StatusConflict {
+		t.Fatalf("expected 409 cancelling a returned booking, got %d: %s", rec.Code, rec.Body.String())
+	}
+}
+
+func TestCancelBookingChargesFeeFromPolicy(t *testing.T) {
+	h := newReservedBooking(t)
+
+	rec := callStatus(t, h.CancelBooking, "1", `{}`)
+	if rec.Code != http.StatusBadRequest {
+		t.Fatalf("expected 400 without reason, got %d: %s", rec.Code, rec.Body.String())
+	}
+
+	// start_rent sudah lewat sehingga tidak ada refund menurut kebijakan default
+	result := decodeTransition(t, callStatus(t, h.CancelBooking, "1", `{"reason": "Berubah pikiran"}`))
+	if result.Status != models.BookingStatusCancelled {
+		t.Errorf("expected status %q, got %q", models.BookingStatusCancelled, result.Status)
+	}
+	if result.CancellationFee != 810000 {
+		t.Errorf("expected cancellation fee 810000, got %d", result.CancellationFee)
+	}
+
+	if rec := callStatus(t, h.NoShowBooking, "9", `{}`); rec.Code != http.StatusNotFound {
+		t.Fatalf("expected 404 for unknown booking, got %d: %s", rec.Code, rec.Body.String())
+	}
+}
@@ -0,0 +1,145 @@
+package controllers
+
+import (
+	"errors"
+	"net/http"
+	"rental-mobil/models"
+	"rental-mobil/repository"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+)
+
+// GetAllBookingTypes mengambil semua jenis booking dengan pagination
+func (h *Handler) GetAllBookingTypes(c echo.Context) error {
+	// Ambil parameter page dan limit dari query params
+	page, err := strconv.Atoi(c.QueryParam("page"))
+	if err != nil || page < 1 {
+		page = 1
+	}
+
+	limit, err := strconv.Atoi(c.QueryParam("limit"))
+	if err != nil || limit < 1 {
+		limit = 10
+	}
+
+	// Hitung offset
+	offset := (page - 1) * limit
+
+	bookingTypes, err := h.Repos.BookingTypes.List(limit, offset)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"message": "Failed to fetch booking types"})
+	}
+
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"page":  page,
+		"limit": limit,
+		"data":  bookingTypes,
+	})
+}
+
+// GetBookingType mengambil satu jenis booking berdasarkan id
+func (h *Handler) GetBookingType(c echo.Context) error {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"message": "Invalid booking type ID"})
+	}
+
+	bookingType, err := h.Repos.BookingTypes.Get(id)
+	if errors.Is(err, repository.ErrNotFound) {
+		return c.JSON(http.StatusNotFound, map[string]string{"message": "Booking type not found"})
+	}
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"message": "Failed to fetch booking type"})
+	}
+
+	return c.JSON(http.StatusOK, bookingType)
+}
+
+// validateBookingType memeriksa nama dan aturan harga jenis booking
+func validateBookingType(bookingType *models.BookingType) string {
+	if bookingType.Name == "" {
+		return "Booking type name is required"
+	}
+	if bookingType.SurchargePercentage < 0 {
+		return "Surcharge percentage must not be negative"
+	}
+	if bookingType.MinDays < 0 {
+		return "Minimum days must not be negative"
+	}
+	for _, tier := range bookingType.CancellationPolicy {
+		if tier.MinDaysBefore < 0 {
+			return "Cancellation tier days must not be negative"
+		}
+		if tier.RefundPercentage < 0 || tier.RefundPercentage > 100 {
+			return "Cancellation refund percentage must be between 0 and 100"
+		}
+	}
+	return ""
+}
+
+// CreateBookingType membuat jenis booking baru
+func (h *Handler) CreateBookingType(c echo.Context) error {
+	bookingType := new(models.BookingType)
+	if err := c.Bind(bookingType); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"message": "Invalid input"})
+	}
+
+	if msg := validateBookingType(bookingType); msg != "" {
+		return c.JSON(http.StatusBadRequest, map[string]string{"message": msg})
+	}
+
+	if err := h.Repos.BookingTypes.Create(bookingType); err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"message": "Failed to create booking type"})
+	}
+
+	return c.JSON(http.StatusCreated, map[string]string{"message": "Booking type created successfully"})
+}
+
+// UpdateBookingType memperbarui jenis booking
+func (h *Handler) UpdateBookingType(c echo.Context) error {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"message": "Invalid booking type ID"})
+	}
+	bookingType := new(models.BookingType)
+	if err := c.Bind(bookingType); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"message": "Invalid input"})
+	}
+
+	if msg := validateBookingType(bookingType); msg != "" {
+		return c.JSON(http.StatusBadRequest, map[string]string{"message": msg})
+	}
+
+	bookingType.ID = id
+	err = h.Repos.BookingTypes.Update(bookingType)
+	if errors.Is(err, repository.ErrNotFound) {
+		return c.JSON(http.StatusNotFound, map[string]string{"message": "Booking type not found"})
+	}
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"message": "Failed to update booking type"})
+	}
+
+	return c.JSON(http.StatusOK, map[string]string{"message": "Booking type updated successfully"})
+}
+
+// DeleteBookingType menghapus jenis booking yang belum dipakai booking manapun
+func (h *Handler) DeleteBookingType(c echo.Context) error {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"message": "Invalid booking type ID"})
+	}
+
+	// Tolak jika jenis booking masih dipakai booking
+	err = h.Repos.BookingTypes.Delete(id)
+	switch {
+	case errors.Is(err, repository.ErrInUse):
+		return c.JSON(http.StatusConflict, map[string]string{"message": "Booking type is still used by bookings"})
+	case errors.Is(err, repository.ErrNotFound):
+		return c.JSON(http.StatusNotFound, map[string]string{"message": "Booking type not found"})
+	case err != nil:
+		return c.JSON(http.StatusInternalServerError, map[string]string{"message": "Failed to delete booking type"})
+	}
+
+	return c.JSON(http.StatusOK, map[string]string{"message": "Booking type deleted successfully"})
+}
@@ -0,0 +1,85 @@
+package controllers
+
+import (
+	"errors"
+	"net/http"
+	"rental-mobil/models"
+	"rental-mobil/repository"
+
+	"github.com/labstack/echo/v4"
+)
+
+// FieldError menjelaskan satu field yang tidak valid
+type FieldError struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+// bookingReference adalah satu foreign key booking yang harus dicek
+type bookingReference struct {
+	field    string
+	id       int
+	required bool
+	exists   func(id int) (bool, error)
+}
+
+// found mengubah hasil Get repository menjadi pengecekan keberadaan
+func found[T any](get func(id int) (T, error)) func(id int) (bool, error) {
+	return func(id int) (bool, error) {
+		_, err := get(id)
+		if errors.Is(err, repository.ErrNotFound) {
+			return false, nil
+		}
+		return err == nil, err
+	}
+}
+
+// validateBookingReferences memastikan semua entitas yang dirujuk booking
+// (customer, mobil, jenis booking, supir) benar-benar ada
+func validateBookingReferences(repos *repository.Repositories, booking *models.Booking) ([]FieldError, error) {
+	references := []bookingReference{
+		{field: "customer_id", id: booking.CustomerID, required: true, exists: repos.Customers.Exists},
+		{field: "car_id", id: booking.CarID, required: true, exists: found(repos.Cars.Get)},
+		{field: "booking_type_id", id: booking.BookingTypeID, required: true, exists: found(repos.BookingTypes.Get)},
+	}
+	if booking.DriverID != nil {
+		references = append(references, bookingReference{field: "driver_id", id: *booking.DriverID, required: true, exists: found(repos.Drivers.Get)})
+	}
+
+	fieldErrors := []FieldError{}
+	for _, ref := range references {
+		if ref.id <= 0 {
+			if ref.required {
+				fieldErrors = append(fieldErrors, FieldError{Field: ref.field, Message: "must be a positive ID"})
+			}
+			continue
+		}
+
+		exists, err := ref.exists(ref.id)
+		if err != nil {
+			return nil, err
+		}
+		if !exists {
+			fieldErrors = append(fieldErrors, FieldError{Field: ref.field, Message: "does not exist"})
+		}
+	}
+
+	return fieldErrors, nil
+}
+
+// checkBookingReferences menjalankan validateBookingReferences dan menulis
+// respons 422 jika ada field yang tidak valid. Mengembalikan true jika
+// handler boleh melanjutkan proses.
+func checkBookingReferences(c echo.Context, repos *repository.Repositories, booking *models.Booking) (bool, error) {
+	fieldErrors, err := validateBookingReferences(repos, booking)
+	if err != nil {
+		return false, c.JSON(http.StatusInternalServerError, map[string]string{"message": "Failed to validate booking references"})
+	}
+	if len(fieldErrors) > 0 {
+		return false, c.JSON(http.StatusUnprocessableEntity, map[string]interface{}{
+			"message": "Invalid booking references",
+			"errors":  fieldErrors,
+		})
+	}
+	return true, nil
+}
@@ -1,16 +1,17 @@
 package controllers
 
 import (
+	"errors"
 	"net/http"
-	"rental-mobil/config"
 	"rental-mobil/models"
+	"rental-mobil/repository"
 	"strconv"
 
 	"github.com/labstack/echo/v4"
 )
 
 // GetAllCars mengambil semua data mobil dengan pagination
-func GetAllCars(c echo.Context) error {
+func (h *Handler) GetAllCars(c echo.Context) error {
 	// Ambil parameter page dan limit dari query params
 	page, err := strconv.Atoi(c.QueryParam("page"))
 	if err != nil || page < 1 {
@@ -25,10 +26,7 @@ func GetAllCars(c echo.Context) error {
 	// Hitung offset
 	offset := (page - 1) * limit
 
-	var cars []models.Car
-	query := `SELECT id, name, stock, daily_rent FROM cars LIMIT $1 OFFSET $2`
-	err = config.DB.Select(&cars, query, limit, offset)
-
+	cars, err := h.Repos.Cars.List(limit, offset, includeDeleted(c))
 	if err != nil {
 		c.Logger().Error("Error fetching cars:", err)
 		return c.JSON(http.StatusInternalServerError, map[string]string{"message": "Failed to fetch cars"})
@@ -44,7 +42,7 @@ func GetAllCars(c echo.Context) error {
 }
 
 // CreateCar membuat data mobil baru
-func CreateCar(c echo.Context) error {
+func (h *Handler) CreateCar(c echo.Context) error {
 	car := new(models.Car)
 	if err := c.Bind(car); err != nil {
 		return c.JSON(http.StatusBadRequest, map[string]string{"message": "Invalid input"})
@@ -60,11 +58,12 @@ func CreateCar(c echo.Context) error {
 	if car.DailyRent <= 0 {
 		return c.JSON(http.StatusBadRequest, map[string]string{"message": "Daily Rent must be greater than zero"})
 	}
+	if car.DepositAmount < 0 {
+		return c.JSON(http.StatusBadRequest, map[string]string{"message": "Deposit amount must not be negative"})
+	}
 
 	// Insert data mobil baru
-	insertQuery := `INSERT INTO cars (name, stock, daily_rent) VALUES ($1, $2, $3)`
-	_, err := config.DB.Exec(insertQuery, car.Name, car.Stock, car.DailyRent)
-	if err != nil {
+	if err := h.Repos.Cars.Create(car); err != nil {
 		return c.JSON(http.StatusInternalServerError, map[string]string{"message": "Failed to create car"})
 	}
 
@@ -72,12 +71,16 @@ func CreateCar(c echo.Context) error {
 }
 
 // UpdateCar memperbarui data mobil
-func UpdateCar(c echo.Context) error {
-	id := c.Param("id")
+func (h *Handler) UpdateCar(c echo.Context) error {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"message": "Invalid car ID"})
+	}
 	car := new(models.Car)
 	if err := c.Bind(car); err != nil {
 		return c.JSON(http.StatusBadRequest, map[string]string{"message": "Invalid input"})
 	}
+	car.ID = id
 
 	// Validasi name, stock, dan daily rent
 	if car.Name == "" {
@@ -89,10 +92,15 @@ func UpdateCar(c echo.Context) error {
 	if car.DailyRent <= 0 {
 		return c.JSON(http.StatusBadRequest, map[string]string{"message": "Daily Rent must be greater than zero"})
 	}
+	if car.DepositAmount < 0 {
+		return c.JSON(http.StatusBadRequest, map[string]string{"message": "Deposit amount must not be negative"})
+	}
 
 	// Update data mobil
-	query := `UPDATE cars SET name=$1, stock=$2, daily_rent=$3 WHERE id=$4`
-	_, err := config.DB.Exec(query, car.Name, car.Stock, car.DailyRent, id)
+	err = h.Repos.Cars.Update(car)
+	if errors.Is(err, repository.ErrNotFound) {
+		return c.JSON(http.StatusNotFound, map[string]string{"message": "Car not found"})
+	}
 	if err != nil {
 		return c.JSON(http.StatusInternalServerError, map[string]string{"message": "Failed to update car"})
 	}
@@ -100,19 +108,142 @@ func UpdateCar(c echo.Context) error {
 	return c.JSON(http.StatusOK, map[string]string{"message": "Car updated successfully"})
 }
 
-// DeleteCar menghapus data mobil
-func DeleteCar(c echo.Context) error {
-	id := c.Param("id")
-	query := `DELETE FROM cars WHERE id=$1`
-	result, err := config.DB.Exec(query, id)
+// DeleteCar menghapus data mobil secara soft delete agar riwayat booking
+// yang merujuk mobil ini tetap utuh. Mobil dengan booking yang belum selesai
+// ditolak kecuali force=true, yang membatalkan booking tersebut sekaligus.
+func (h *Handler) DeleteCar(c echo.Context) error {
+	id, err := strconv.Atoi(c.Param("id"))
 	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"message": "Invalid car ID"})
+	}
+	force, _ := strconv.ParseBool(c.QueryParam("force"))
+
+	cancelled, err := h.Repos.Cars.Delete(id, force)
+	var blocked *repository.BlockedError
+	switch {
+	case errors.Is(err, repository.ErrNotFound):
+		return c.JSON(http.StatusNotFound, map[string]string{"message": "Car not found"})
+	case errors.As(err, &blocked):
+		return blockedDeleteResponse(c, blocked)
+	case err != nil:
 		return c.JSON(http.StatusInternalServerError, map[string]string{"message": "Failed to delete car"})
 	}
 
-	rowsAffected, _ := result.RowsAffected()
-	if rowsAffected == 0 {
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"message":            "Car deleted successfully",
+		"cancelled_bookings": cancelled,
+	})
+}
+
+// RestoreCar mengembalikan mobil yang sudah di-soft delete
+func (h *Handler) RestoreCar(c echo.Context) error {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"message": "Invalid car ID"})
+	}
+
+	err = h.Repos.Cars.Restore(id)
+	if errors.Is(err, repository.ErrNotFound) {
+		return c.JSON(http.StatusNotFound, map[string]string{"message": "Deleted car not found"})
+	}
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"message": "Failed to restore car"})
+	}
+
+	return c.JSON(http.StatusOK, map[string]string{"message": "Car restored successfully"})
+}
+
+// maxAvailabilityDays membatasi panjang rentang kalender ketersediaan
+const maxAvailabilityDays = 92
+
+// GetCarAvailability mengembalikan jumlah unit mobil yang tersedia per hari
+// untuk rentang tanggal from sampai to (inklusif)
+func (h *Handler) GetCarAvailability(c echo.Context) error {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"message": "Invalid car ID"})
+	}
+
+	from, to, msg := parseDateRange(c)
+	if msg != "" {
+		return c.JSON(http.StatusBadRequest, map[string]string{"message": msg})
+	}
+	if to.Sub(from).Hours()/24 >= maxAvailabilityDays {
+		return c.JSON(http.StatusBadRequest, map[string]string{"message": "Date range must not exceed 92 days"})
+	}
+
+	car, err := h.Repos.Cars.Get(id)
+	if errors.Is(err, repository.ErrNotFound) {
 		return c.JSON(http.StatusNotFound, map[string]string{"message": "Car not found"})
 	}
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"message": "Failed to fetch car data"})
+	}
+
+	// Hitung ketersediaan tiap hari dengan logika irisan yang sama seperti booking
+	days := []map[string]interface{}{}
+	for day := from; !day.After(to); day = day.AddDate(0, 0, 1) {
+		start := day.Format("2006-01-02")
+		end := day.AddDate(0, 0, 1).Format("2006-01-02")
+		booked, err := h.Repos.Bookings.CountOverlapping(car.ID, start, end)
+		if err != nil {
+			return c.JSON(http.StatusInternalServerError, map[string]string{"message": "Failed to check car availability"})
+		}
+		available := car.Stock - booked
+		if available < 0 {
+			available = 0
+		}
+		days = append(days, map[string]interface{}{
+			"date":      start,
+			"booked":    booked,
+			"available": available,
+		})
+	}
 
-	return c.JSON(http.StatusOK, map[string]string{"message": "Car deleted successfully"})
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"car":  car,
+		"from": from.Format("2006-01-02"),
+		"to":   to.Format("2006-01-02"),
+		"data": days,
+	})
+}
+
+// GetAvailableCars mengembalikan semua mobil yang masih memiliki unit
+// tersedia untuk rentang sewa from sampai to
+func (h *Handler) GetAvailableCars(c echo.Context) error {
+	from, to, msg := parseDateRange(c)
+	if msg != "" {
+		return c.JSON(http.StatusBadRequest, map[string]string{"message": msg})
+	}
+	if !to.After(from) {
+		return c.JSON(http.StatusBadRequest, map[string]string{"message": "To date must be after from date"})
+	}
+	start := from.Format("2006-01-02")
+	end := to.Format("2006-01-02")
+
+	cars, err := h.Repos.Cars.ListActive()
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"message": "Failed to fetch cars"})
+	}
+
+	available := []map[string]interface{}{}
+	for _, car := range cars {
+		booked, err := h.Repos.Bookings.CountOverlapping(car.ID, start, end)
+		if err != nil {
+			return c.JSON(http.StatusInternalServerError, map[string]string{"message": "Failed to check car availability"})
+		}
+		if booked >= car.Stock {
+			continue
+		}
+		available = append(available, map[string]interface{}{
+			"car":       car,
+			"available": car.Stock - booked,
+		})
+	}
+
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"from": start,
+		"to":   end,
+		"data": available,
+	})
 }
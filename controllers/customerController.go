@@ -1,15 +1,17 @@
 package controllers
 
 import (
+	"errors"
 	"net/http"
-	"rental-mobil/config"
 	"rental-mobil/models"
+	"rental-mobil/repository"
 	"strconv"
+	"time"
 
 	"github.com/labstack/echo/v4"
 )
 
-func GetAllCustomers(c echo.Context) error {
+func (h *Handler) GetAllCustomers(c echo.Context) error {
 	// Ambil parameter page dan limit dari query params
 	page, err := strconv.Atoi(c.QueryParam("page"))
 	if err != nil || page < 1 {
@@ -24,9 +26,7 @@ func GetAllCustomers(c echo.Context) error {
 	// Hitung offset
 	offset := (page - 1) * limit
 
-	var customers []models.Customer
-	query := `SELECT * FROM customers LIMIT $1 OFFSET $2`
-	err = config.DB.Select(&customers, query, limit, offset)
+	customers, err := h.Repos.Customers.List(limit, offset, includeDeleted(c))
 	if err != nil {
 		return c.JSON(http.StatusInternalServerError, map[string]string{"message": "Failed to fetch customers"})
 	}
@@ -39,40 +39,52 @@ func GetAllCustomers(c echo.Context) error {
 	})
 }
 
-func CreateCustomer(c echo.Context) error {
+func (h *Handler) CreateCustomer(c echo.Context) error {
 	customer := new(models.Customer)
 	if err := c.Bind(customer); err != nil {
 		return c.JSON(http.StatusBadRequest, map[string]string{"message": "Invalid input"})
 	}
 
-	existsQuery := `SELECT COUNT(*) FROM customers WHERE nik = $1`
-	var count int
-	err := config.DB.QueryRow(existsQuery, customer.NIK).Scan(&count)
+	nikTaken, err := h.Repos.Customers.NIKExists(customer.NIK, 0)
 	if err != nil {
 		return c.JSON(http.StatusInternalServerError, map[string]string{"message": "Failed to check NIK"})
 	}
 
-	if count > 0 {
+	if nikTaken {
 		return c.JSON(http.StatusConflict, map[string]string{"message": "NIK already registered"})
 	}
 
-	insertQuery := `INSERT INTO customers (name, nik, phone) VALUES ($1, $2, $3)`
-	_, err = config.DB.Exec(insertQuery, customer.Name, customer.NIK, customer.Phone)
-	if err != nil {
+	// Validasi membership jika diisi
+	if customer.MembershipID != nil {
+		exists, err := h.Repos.Memberships.Exists(*customer.MembershipID)
+		if err != nil {
+			return c.JSON(http.StatusInternalServerError, map[string]string{"message": "Failed to check membership"})
+		}
+		if !exists {
+			return c.JSON(http.StatusBadRequest, map[string]string{"message": "Membership not found"})
+		}
+	}
+
+	// Membership awal ikut dicatat ke riwayat
+	if err := h.Repos.Customers.Create(customer); err != nil {
 		return c.JSON(http.StatusInternalServerError, map[string]string{"message": "Failed to create customer"})
 	}
 
 	return c.JSON(http.StatusCreated, map[string]string{"message": "Customer created successfully"})
 }
 
-func UpdateCustomer(c echo.Context) error {
-    id := c.Param("id")
+func (h *Handler) UpdateCustomer(c echo.Context) error {
+    id, err := strconv.Atoi(c.Param("id"))
+    if err != nil {
+        return c.JSON(http.StatusBadRequest, map[string]string{"message": "Invalid customer ID"})
+    }
 
     // Cek apakah customer dengan id tersebut ada
-    var count int
-    checkQuery := `SELECT COUNT(*) FROM customers WHERE id = $1`
-    err := config.DB.QueryRow(checkQuery, id).Scan(&count)
-    if err != nil || count == 0 {
+    exists, err := h.Repos.Customers.Exists(id)
+    if err != nil {
+        return c.JSON(http.StatusInternalServerError, map[string]string{"message": "Failed to check customer"})
+    }
+    if !exists {
         return c.JSON(http.StatusNotFound, map[string]string{"message": "Customer not found"})
     }
 
@@ -83,30 +95,38 @@ func UpdateCustomer(c echo.Context) error {
     }
 
     // Validasi jika hanya field tertentu yang diubah
-    if customer.Name == "" && customer.NIK == "" && customer.Phone == "" {
+    if customer.Name == "" && customer.NIK == "" && customer.Phone == "" && customer.MembershipID == nil {
         return c.JSON(http.StatusBadRequest, map[string]string{"message": "No valid fields to update"})
     }
 
     // Cek apakah NIK sudah ada (kecuali untuk id yang sama)
     if customer.NIK != "" {
-        existsQuery := `SELECT COUNT(*) FROM customers WHERE nik = $1 AND id != $2`
-        var count int
-        err := config.DB.QueryRow(existsQuery, customer.NIK, id).Scan(&count)
+        nikTaken, err := h.Repos.Customers.NIKExists(customer.NIK, id)
         if err != nil {
             return c.JSON(http.StatusInternalServerError, map[string]string{"message": "Failed to check NIK"})
         }
-        if count > 0 {
+        if nikTaken {
             return c.JSON(http.StatusConflict, map[string]string{"message": "NIK already registered"})
         }
     }
 
-    // Update hanya field yang ada
-    query := `UPDATE customers SET 
-        name = COALESCE($1, name), 
-        nik = COALESCE($2, nik), 
-        phone = COALESCE($3, phone) 
-        WHERE id = $4`
-    _, err = config.DB.Exec(query, customer.Name, customer.NIK, customer.Phone, id)
+    // Validasi membership jika diisi
+    if customer.MembershipID != nil {
+        exists, err := h.Repos.Memberships.Exists(*customer.MembershipID)
+        if err != nil {
+            return c.JSON(http.StatusInternalServerError, map[string]string{"message": "Failed to check membership"})
+        }
+        if !exists {
+            return c.JSON(http.StatusBadRequest, map[string]string{"message": "Membership not found"})
+        }
+    }
+
+    // Perubahan membership ikut dicatat ke riwayat
+    customer.ID = id
+    err = h.Repos.Customers.Update(customer)
+    if errors.Is(err, repository.ErrNotFound) {
+        return c.JSON(http.StatusNotFound, map[string]string{"message": "Customer not found"})
+    }
     if err != nil {
         return c.JSON(http.StatusInternalServerError, map[string]string{"message": "Failed to update customer"})
     }
@@ -115,27 +135,117 @@ func UpdateCustomer(c echo.Context) error {
 }
 
 
-func DeleteCustomer(c echo.Context) error {
-	id := c.Param("id")
+// DeleteCustomer menghapus pelanggan secara soft delete. Pelanggan dengan
+// booking yang belum selesai ditolak kecuali force=true, yang membatalkan
+// booking tersebut di transaksi yang sama.
+func (h *Handler) DeleteCustomer(c echo.Context) error {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"message": "Invalid customer ID"})
+	}
+	force, _ := strconv.ParseBool(c.QueryParam("force"))
 
-	// Cek apakah customer dengan id tersebut ada
-	var count int
-	checkQuery := `SELECT COUNT(*) FROM customers WHERE id = $1`
-	err := config.DB.QueryRow(checkQuery, id).Scan(&count)
-	if err != nil || count == 0 {
+	// Soft delete agar booking dan riwayat membership pelanggan tetap utuh
+	cancelled, err := h.Repos.Customers.Delete(id, force)
+	var blocked *repository.BlockedError
+	switch {
+	case errors.Is(err, repository.ErrNotFound):
 		return c.JSON(http.StatusNotFound, map[string]string{"message": "Customer not found"})
+	case errors.As(err, &blocked):
+		return blockedDeleteResponse(c, blocked)
+	case err != nil:
+		return c.JSON(http.StatusInternalServerError, map[string]string{"message": "Failed to delete customer"})
 	}
 
-	query := `DELETE FROM customers WHERE id=$1`
-	result, err := config.DB.Exec(query, id)
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"message":            "Customer deleted successfully",
+		"cancelled_bookings": cancelled,
+	})
+}
+
+// RestoreCustomer mengembalikan pelanggan yang sudah di-soft delete
+func (h *Handler) RestoreCustomer(c echo.Context) error {
+	id, err := strconv.Atoi(c.Param("id"))
 	if err != nil {
-		return c.JSON(http.StatusInternalServerError, map[string]string{"message": "Failed to delete customer"})
+		return c.JSON(http.StatusBadRequest, map[string]string{"message": "Invalid customer ID"})
+	}
+
+	err = h.Repos.Customers.Restore(id)
+	if errors.Is(err, repository.ErrNotFound) {
+		return c.JSON(http.StatusNotFound, map[string]string{"message": "Deleted customer not found"})
+	}
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"message": "Failed to restore customer"})
 	}
 
-	rowsAffected, _ := result.RowsAffected()
-	if rowsAffected == 0 {
+	return c.JSON(http.StatusOK, map[string]string{"message": "Customer restored successfully"})
+}
+
+// UpdateCustomerMembership mengganti membership pelanggan dan mencatat
+// riwayat perubahan beserta tanggal berlakunya
+func (h *Handler) UpdateCustomerMembership(c echo.Context) error {
+	customerID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"message": "Invalid customer ID"})
+	}
+
+	var input struct {
+		MembershipID  *int   `json:"membership_id"`
+		EffectiveDate string `json:"effective_date"`
+	}
+	if err := c.Bind(&input); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"message": "Invalid input"})
+	}
+
+	// Tanggal berlaku default hari ini dan tidak boleh di masa depan
+	effectiveDate := time.Now().Format("2006-01-02")
+	if input.EffectiveDate != "" {
+		date, err := time.Parse("2006-01-02", input.EffectiveDate)
+		if err != nil {
+			return c.JSON(http.StatusBadRequest, map[string]string{"message": "Invalid effective date format"})
+		}
+		if date.After(time.Now()) {
+			return c.JSON(http.StatusBadRequest, map[string]string{"message": "Effective date cannot be in the future"})
+		}
+		effectiveDate = input.EffectiveDate
+	}
+
+	// membership_id null berarti membership pelanggan dicabut
+	if input.MembershipID != nil {
+		exists, err := h.Repos.Memberships.Exists(*input.MembershipID)
+		if err != nil {
+			return c.JSON(http.StatusInternalServerError, map[string]string{"message": "Failed to check membership"})
+		}
+		if !exists {
+			return c.JSON(http.StatusBadRequest, map[string]string{"message": "Membership not found"})
+		}
+	}
+
+	err = h.Repos.Customers.ChangeMembership(customerID, input.MembershipID, effectiveDate)
+	if errors.Is(err, repository.ErrNotFound) {
 		return c.JSON(http.StatusNotFound, map[string]string{"message": "Customer not found"})
 	}
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"message": "Failed to update customer membership"})
+	}
+
+	return c.JSON(http.StatusOK, map[string]string{"message": "Customer membership updated successfully"})
+}
+
+// GetCustomerMembershipHistory mengambil riwayat perubahan membership pelanggan
+func (h *Handler) GetCustomerMembershipHistory(c echo.Context) error {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"message": "Invalid customer ID"})
+	}
+
+	history, err := h.Repos.Customers.MembershipHistory(id)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"message": "Failed to fetch membership history"})
+	}
 
-	return c.JSON(http.StatusOK, map[string]string{"message": "Customer deleted successfully"})
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"customer_id": id,
+		"data":        history,
+	})
 }
@@ -0,0 +1,209 @@
+package controllers
+
+import (
+	"database/sql"
+	"net/http"
+	"rental-mobil/models"
+	"rental-mobil/services"
+	"strconv"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/labstack/echo/v4"
+)
+
+var deductionCategories = map[string]bool{
+	models.DeductionDamage:   true,
+	models.DeductionFuel:     true,
+	models.DeductionCleaning: true,
+}
+
+// GetBookingDeposit mengambil riwayat dan ringkasan uang jaminan booking
+func (h *Handler) GetBookingDeposit(c echo.Context) error {
+	bookingID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"message": "Invalid booking ID"})
+	}
+
+	var count int
+	err = h.DB.Get(&count, `SELECT COUNT(*) FROM bookings WHERE id = $1 AND deleted_at IS NULL`, bookingID)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"message": "Failed to fetch booking"})
+	}
+	if count == 0 {
+		return c.JSON(http.StatusNotFound, map[string]string{"message": "Booking not found"})
+	}
+
+	summary, err := services.GetDepositSummary(h.DB, bookingID)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"message": "Failed to calculate deposit"})
+	}
+
+	transactions := []models.DepositTransaction{}
+	query := `SELECT id, booking_id, type, category, method, amount, reason, created_at
+        FROM deposit_transactions WHERE booking_id = $1 ORDER BY created_at, id`
+	err = h.DB.Select(&transactions, query, bookingID)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"message": "Failed to fetch deposit transactions"})
+	}
+
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"booking_id": bookingID,
+		"summary":    summary,
+		"data":       transactions,
+	})
+}
+
+// HoldBookingDeposit mencatat uang jaminan yang diterima saat pengambilan mobil
+func (h *Handler) HoldBookingDeposit(c echo.Context) error {
+	var input struct {
+		Method string       `json:"method"`
+		Amount models.Money `json:"amount"`
+	}
+	if err := c.Bind(&input); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"message": "Invalid input"})
+	}
+	if !paymentMethods[input.Method] {
+		return c.JSON(http.StatusBadRequest, map[string]string{"message": "Payment method must be cash, bank_transfer or qris"})
+	}
+
+	return h.depositTransaction(c, func(tx *sqlx.Tx, bookingID int, status string, summary *services.DepositSummary) (*models.DepositTransaction, int, string) {
+		if !models.IsActiveBookingStatus(status) {
+			return nil, http.StatusConflict, "Deposit can only be held for reserved or picked up bookings"
+		}
+		if summary.Required > 0 && summary.Held >= summary.Required {
+			return nil, http.StatusConflict, "Deposit has already been held in full"
+		}
+		if summary.Required == 0 && summary.Held > 0 {
+			return nil, http.StatusConflict, "Deposit has already been held"
+		}
+
+		// Hold berikutnya menambah kekurangan sampai uang jaminan mobil terpenuhi;
+		// nominal default adalah kekurangan tersebut
+		outstanding := summary.Required - summary.Held
+		amount := input.Amount
+		if amount == 0 {
+			amount = outstanding
+		}
+		if amount <= 0 {
+			return nil, http.StatusBadRequest, "Amount must be greater than zero"
+		}
+		if summary.Required > 0 && amount > outstanding {
+			return nil, http.StatusBadRequest, "Amount exceeds outstanding deposit of " + outstanding.String()
+		}
+		return &models.DepositTransaction{Type: models.DepositHold, Method: input.Method, Amount: amount}, 0, ""
+	})
+}
+
+// DeductBookingDeposit memotong uang jaminan untuk kerusakan, bahan bakar
+// atau kebersihan beserta alasannya
+func (h *Handler) DeductBookingDeposit(c echo.Context) error {
+	var input struct {
+		Category string       `json:"category"`
+		Amount   models.Money `json:"amount"`
+		Reason   string       `json:"reason"`
+	}
+	if err := c.Bind(&input); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"message": "Invalid input"})
+	}
+	if !deductionCategories[input.Category] {
+		return c.JSON(http.StatusBadRequest, map[string]string{"message": "Category must be damage, fuel or cleaning"})
+	}
+	if input.Reason == "" {
+		return c.JSON(http.StatusBadRequest, map[string]string{"message": "Reason is required"})
+	}
+	if input.Amount <= 0 {
+		return c.JSON(http.StatusBadRequest, map[string]string{"message": "Amount must be greater than zero"})
+	}
+
+	return h.depositTransaction(c, func(tx *sqlx.Tx, bookingID int, status string, summary *services.DepositSummary) (*models.DepositTransaction, int, string) {
+		if status != models.BookingStatusPickedUp && status != models.BookingStatusReturned {
+			return nil, http.StatusConflict, "Deductions can only be made for picked up or returned bookings"
+		}
+		if input.Amount > summary.Remaining {
+			return nil, http.StatusBadRequest, "Deduction exceeds remaining deposit of " + summary.Remaining.String()
+		}
+		return &models.DepositTransaction{Type: models.DepositDeduction, Category: input.Category, Amount: input.Amount, Reason: input.Reason}, 0, ""
+	})
+}
+
+// ReleaseBookingDeposit mengembalikan sisa uang jaminan setelah mobil kembali,
+// atau setelah booking dibatalkan maupun pelanggan tidak datang
+func (h *Handler) ReleaseBookingDeposit(c echo.Context) error {
+	var input struct {
+		Method string `json:"method"`
+	}
+	if err := c.Bind(&input); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"message": "Invalid input"})
+	}
+	if !paymentMethods[input.Method] {
+		return c.JSON(http.StatusBadRequest, map[string]string{"message": "Payment method must be cash, bank_transfer or qris"})
+	}
+
+	return h.depositTransaction(c, func(tx *sqlx.Tx, bookingID int, status string, summary *services.DepositSummary) (*models.DepositTransaction, int, string) {
+		if status != models.BookingStatusReturned && status != models.BookingStatusCancelled && status != models.BookingStatusNoShow {
+			return nil, http.StatusConflict, "Deposit can only be released after the car is returned or the booking is cancelled"
+		}
+		if summary.Remaining <= 0 {
+			return nil, http.StatusConflict, "No remaining deposit to release"
+		}
+		return &models.DepositTransaction{Type: models.DepositRelease, Method: input.Method, Amount: summary.Remaining}, 0, ""
+	})
+}
+
+// depositTransaction mengunci booking, menjalankan buildFn untuk memvalidasi
+// dan menyusun transaksi, lalu menyimpannya
+func (h *Handler) depositTransaction(c echo.Context, buildFn func(tx *sqlx.Tx, bookingID int, status string, summary *services.DepositSummary) (*models.DepositTransaction, int, string)) error {
+	bookingID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"message": "Invalid booking ID"})
+	}
+
+	tx, err := h.DB.Beginx()
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"message": "Failed to start transaction"})
+	}
+	defer tx.Rollback()
+
+	var status string
+	err = tx.Get(&status, `SELECT status FROM bookings WHERE id = $1 AND deleted_at IS NULL FOR UPDATE`, bookingID)
+	if err == sql.ErrNoRows {
+		return c.JSON(http.StatusNotFound, map[string]string{"message": "Booking not found"})
+	}
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"message": "Failed to fetch booking"})
+	}
+
+	summary, err := services.GetDepositSummary(tx, bookingID)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"message": "Failed to calculate deposit"})
+	}
+
+	transaction, code, msg := buildFn(tx, bookingID, status, summary)
+	if msg != "" {
+		return c.JSON(code, map[string]string{"message": msg})
+	}
+	transaction.BookingID = bookingID
+
+	insertQuery := `INSERT INTO deposit_transactions (booking_id, type, category, method, amount, reason, created_at)
+        VALUES ($1, $2, $3, $4, $5, $6, NOW()) RETURNING id, created_at`
+	err = tx.QueryRowx(insertQuery, transaction.BookingID, transaction.Type, transaction.Category, transaction.Method,
+		transaction.Amount, transaction.Reason).Scan(&transaction.ID, &transaction.CreatedAt)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"message": "Failed to record deposit transaction"})
+	}
+
+	summary, err = services.GetDepositSummary(tx, bookingID)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"message": "Failed to calculate deposit"})
+	}
+
+	if err = tx.Commit(); err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"message": "Failed to record deposit transaction"})
+	}
+
+	return c.JSON(http.StatusCreated, map[string]interface{}{
+		"message":     "Deposit " + transaction.Type + " recorded successfully",
+		"transaction": transaction,
+		"summary":     summary,
+	})
+}
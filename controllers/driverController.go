@@ -0,0 +1,239 @@
+package controllers
+
+import (
+	"errors"
+	"net/http"
+	"rental-mobil/models"
+	"rental-mobil/repository"
+	"strconv"
+	"time"
+
+	"github.com/labstack/echo/v4"
+)
+
+// GetAllDrivers mengambil semua data supir dengan pagination
+func (h *Handler) GetAllDrivers(c echo.Context) error {
+	// Ambil parameter page dan limit dari query params
+	page, err := strconv.Atoi(c.QueryParam("page"))
+	if err != nil || page < 1 {
+		page = 1
+	}
+
+	limit, err := strconv.Atoi(c.QueryParam("limit"))
+	if err != nil || limit < 1 {
+		limit = 10
+	}
+
+	// Hitung offset
+	offset := (page - 1) * limit
+
+	drivers, err := h.Repos.Drivers.List(limit, offset)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"message": "Failed to fetch drivers"})
+	}
+
+	// Kembalikan data dengan informasi pagination
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"page":  page,
+		"limit": limit,
+		"data":  drivers,
+	})
+}
+
+// GetDriver mengambil data satu supir berdasarkan id
+func (h *Handler) GetDriver(c echo.Context) error {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"message": "Invalid driver ID"})
+	}
+
+	driver, err := h.Repos.Drivers.Get(id)
+	if errors.Is(err, repository.ErrNotFound) {
+		return c.JSON(http.StatusNotFound, map[string]string{"message": "Driver not found"})
+	}
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"message": "Failed to fetch driver"})
+	}
+
+	return c.JSON(http.StatusOK, driver)
+}
+
+// CreateDriver membuat data supir baru
+func (h *Handler) CreateDriver(c echo.Context) error {
+	driver := new(models.Driver)
+	if err := c.Bind(driver); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"message": "Invalid input"})
+	}
+
+	// Validasi name, nik, dan daily cost
+	if driver.Name == "" {
+		return c.JSON(http.StatusBadRequest, map[string]string{"message": "Driver name is required"})
+	}
+	if driver.NIK == "" {
+		return c.JSON(http.StatusBadRequest, map[string]string{"message": "NIK is required"})
+	}
+	if driver.DailyCost <= 0 {
+		return c.JSON(http.StatusBadRequest, map[string]string{"message": "Daily Cost must be greater than zero"})
+	}
+
+	nikTaken, err := h.Repos.Drivers.NIKExists(driver.NIK, 0)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"message": "Failed to check NIK"})
+	}
+
+	if nikTaken {
+		return c.JSON(http.StatusConflict, map[string]string{"message": "NIK already registered"})
+	}
+
+	if err := h.Repos.Drivers.Create(driver); err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"message": "Failed to create driver"})
+	}
+
+	return c.JSON(http.StatusCreated, map[string]string{"message": "Driver created successfully"})
+}
+
+// UpdateDriver memperbarui data supir
+func (h *Handler) UpdateDriver(c echo.Context) error {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"message": "Invalid driver ID"})
+	}
+
+	// Cek apakah supir dengan id tersebut ada
+	_, err = h.Repos.Drivers.Get(id)
+	if errors.Is(err, repository.ErrNotFound) {
+		return c.JSON(http.StatusNotFound, map[string]string{"message": "Driver not found"})
+	}
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"message": "Failed to fetch driver"})
+	}
+
+	driver := new(models.Driver)
+	if err := c.Bind(driver); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"message": "Invalid input"})
+	}
+
+	// Validasi name, nik, dan daily cost
+	if driver.Name == "" {
+		return c.JSON(http.StatusBadRequest, map[string]string{"message": "Driver name is required"})
+	}
+	if driver.NIK == "" {
+		return c.JSON(http.StatusBadRequest, map[string]string{"message": "NIK is required"})
+	}
+	if driver.DailyCost <= 0 {
+		return c.JSON(http.StatusBadRequest, map[string]string{"message": "Daily Cost must be greater than zero"})
+	}
+
+	// Cek apakah NIK sudah dipakai supir lain
+	nikTaken, err := h.Repos.Drivers.NIKExists(driver.NIK, id)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"message": "Failed to check NIK"})
+	}
+	if nikTaken {
+		return c.JSON(http.StatusConflict, map[string]string{"message": "NIK already registered"})
+	}
+
+	driver.ID = id
+	err = h.Repos.Drivers.Update(driver)
+	if errors.Is(err, repository.ErrNotFound) {
+		return c.JSON(http.StatusNotFound, map[string]string{"message": "Driver not found"})
+	}
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"message": "Failed to update driver"})
+	}
+
+	return c.JSON(http.StatusOK, map[string]string{"message": "Driver updated successfully"})
+}
+
+// DeleteDriver menghapus data supir
+func (h *Handler) DeleteDriver(c echo.Context) error {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"message": "Invalid driver ID"})
+	}
+
+	// Tolak jika supir masih tercatat di booking
+	err = h.Repos.Drivers.Delete(id)
+	switch {
+	case errors.Is(err, repository.ErrInUse):
+		return c.JSON(http.StatusConflict, map[string]string{"message": "Driver is still assigned to bookings"})
+	case errors.Is(err, repository.ErrNotFound):
+		return c.JSON(http.StatusNotFound, map[string]string{"message": "Driver not found"})
+	case err != nil:
+		return c.JSON(http.StatusInternalServerError, map[string]string{"message": "Failed to delete driver"})
+	}
+
+	return c.JSON(http.StatusOK, map[string]string{"message": "Driver deleted successfully"})
+}
+
+// GetAvailableDrivers mengembalikan supir yang tidak memiliki booking
+// beririsan dengan rentang sewa from sampai to
+func (h *Handler) GetAvailableDrivers(c echo.Context) error {
+	from, to, msg := parseDateRange(c)
+	if msg != "" {
+		return c.JSON(http.StatusBadRequest, map[string]string{"message": msg})
+	}
+	if !to.After(from) {
+		return c.JSON(http.StatusBadRequest, map[string]string{"message": "To date must be after from date"})
+	}
+	start := from.Format("2006-01-02")
+	end := to.Format("2006-01-02")
+
+	drivers, err := h.Repos.Drivers.ListAvailable(start, end)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"message": "Failed to fetch available drivers"})
+	}
+
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"from": start,
+		"to":   end,
+		"data": drivers,
+	})
+}
+
+// GetDriverIncentives mengambil insentif supir beserta totalnya untuk
+// keperluan penggajian, dapat difilter berdasarkan tanggal selesai sewa
+func (h *Handler) GetDriverIncentives(c echo.Context) error {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"message": "Invalid driver ID"})
+	}
+
+	_, err = h.Repos.Drivers.Get(id)
+	if errors.Is(err, repository.ErrNotFound) {
+		return c.JSON(http.StatusNotFound, map[string]string{"message": "Driver not found"})
+	}
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"message": "Failed to fetch driver"})
+	}
+
+	// Filter tanggal bersifat opsional
+	from := c.QueryParam("from")
+	if from != "" {
+		if _, err := time.Parse("2006-01-02", from); err != nil {
+			return c.JSON(http.StatusBadRequest, map[string]string{"message": "Invalid from date format"})
+		}
+	}
+	to := c.QueryParam("to")
+	if to != "" {
+		if _, err := time.Parse("2006-01-02", to); err != nil {
+			return c.JSON(http.StatusBadRequest, map[string]string{"message": "Invalid to date format"})
+		}
+	}
+
+	incentives, err := h.Repos.Drivers.Incentives(id, from, to)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"message": "Failed to fetch driver incentives"})
+	}
+
+	var total models.Money
+	for _, incentive := range incentives {
+		total += incentive.Incentive
+	}
+
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"driver_id": id,
+		"total":     total,
+		"data":      incentives,
+	})
+}
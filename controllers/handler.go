@@ -0,0 +1,38 @@
+package controllers
+
+import (
+	"net/http"
+	"rental-mobil/repository"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/labstack/echo/v4"
+)
+
+// Handler menampung dependensi seluruh controller. Repos dipakai untuk
+// agregat mobil, pelanggan, booking, supir, membership dan jenis booking
+// termasuk validasi, perhitungan harga dan perpindahan status booking; DB
+// hanya dipakai oleh endpoint invoice, pembayaran, tagihan online dan uang
+// jaminan, sehingga boleh nil saat controller lain diuji dengan
+// repository.NewMemory.
+type Handler struct {
+	Repos *repository.Repositories
+	DB    *sqlx.DB
+}
+
+// NewHandler membuat Handler dengan repository dan koneksi database yang diberikan
+func NewHandler(repos *repository.Repositories, db *sqlx.DB) *Handler {
+	return &Handler{Repos: repos, DB: db}
+}
+
+// blockedDeleteResponse menulis respons 409 berisi booking yang menghalangi
+// penghapusan mobil atau pelanggan
+func blockedDeleteResponse(c echo.Context, blocked *repository.BlockedError) error {
+	message := "There are active bookings; cancel them first or retry with force=true"
+	if blocked.PickedUp {
+		message = "Cars in these bookings have not been returned yet"
+	}
+	return c.JSON(http.StatusConflict, map[string]interface{}{
+		"message":  message,
+		"bookings": blocked.Bookings,
+	})
+}
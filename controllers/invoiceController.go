@@ -0,0 +1,111 @@
+package controllers
+
+import (
+	"database/sql"
+	"net/http"
+	"rental-mobil/models"
+	"rental-mobil/services"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+)
+
+// loadBookingInvoice mengambil data invoice booking untuk ditampilkan.
+// Booking lama yang belum punya invoice akan dibuatkan dari nominal yang
+// tersimpan di booking.
+func (h *Handler) loadBookingInvoice(c echo.Context) (*services.InvoiceView, int, string) {
+	bookingID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return nil, http.StatusBadRequest, "Invalid booking ID"
+	}
+
+	var view struct {
+		models.Booking
+		CustomerName string `db:"customer_name"`
+		CarName      string `db:"car_name"`
+	}
+	query := `SELECT b.id, b.customer_id, b.car_id, b.start_rent, b.end_rent, b.total_cost, b.total_driver_cost,
+            b.status, b.late_fee, b.return_date, b.cancellation_fee, c.name AS customer_name, cr.name AS car_name
+        FROM bookings b
+        JOIN customers c ON c.id = b.customer_id
+        JOIN cars cr ON cr.id = b.car_id
+        WHERE b.id = $1`
+	err = h.DB.Get(&view, query, bookingID)
+	if err == sql.ErrNoRows {
+		return nil, http.StatusNotFound, "Booking not found"
+	}
+	if err != nil {
+		return nil, http.StatusInternalServerError, "Failed to fetch booking"
+	}
+
+	invoice, err := services.GetBookingInvoice(h.DB, bookingID)
+	if err == sql.ErrNoRows {
+		invoice, err = h.generateBookingInvoice(&view.Booking)
+	}
+	if err != nil {
+		return nil, http.StatusInternalServerError, "Failed to fetch invoice"
+	}
+
+	startRent, _ := services.ParseRentDate(view.StartRent)
+	endRent, _ := services.ParseRentDate(view.EndRent)
+	return &services.InvoiceView{
+		Invoice:      invoice,
+		CustomerName: view.CustomerName,
+		CarName:      view.CarName,
+		StartRent:    startRent.Format("02-01-2006"),
+		EndRent:      endRent.Format("02-01-2006"),
+	}, 0, ""
+}
+
+// generateBookingInvoice membuat invoice untuk booking yang belum memilikinya
+func (h *Handler) generateBookingInvoice(booking *models.Booking) (*models.Invoice, error) {
+	tx, err := h.DB.Beginx()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	if err := services.SaveStoredBookingInvoice(tx, booking); err != nil {
+		return nil, err
+	}
+
+	invoice, err := services.GetBookingInvoice(tx, booking.ID)
+	if err != nil {
+		return nil, err
+	}
+	return invoice, tx.Commit()
+}
+
+// GetBookingInvoice mengembalikan invoice booking dalam format JSON
+func (h *Handler) GetBookingInvoice(c echo.Context) error {
+	view, status, msg := h.loadBookingInvoice(c)
+	if msg != "" {
+		return c.JSON(status, map[string]string{"message": msg})
+	}
+	return c.JSON(http.StatusOK, view.Invoice)
+}
+
+// GetBookingInvoiceHTML menampilkan invoice booking yang siap dicetak
+func (h *Handler) GetBookingInvoiceHTML(c echo.Context) error {
+	view, status, msg := h.loadBookingInvoice(c)
+	if msg != "" {
+		return c.JSON(status, map[string]string{"message": msg})
+	}
+
+	html, err := services.RenderInvoiceHTML(*view)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"message": "Failed to render invoice"})
+	}
+	return c.HTMLBlob(http.StatusOK, html)
+}
+
+// GetBookingInvoicePDF mengunduh invoice booking dalam format PDF
+func (h *Handler) GetBookingInvoicePDF(c echo.Context) error {
+	view, status, msg := h.loadBookingInvoice(c)
+	if msg != "" {
+		return c.JSON(status, map[string]string{"message": msg})
+	}
+
+	c.Response().Header().Set(echo.HeaderContentDisposition, `inline; filename="`+view.Invoice.Number+`.pdf"`)
+	return c.Blob(http.StatusOK, "application/pdf", services.RenderInvoicePDF(*view))
+}
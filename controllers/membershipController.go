@@ -0,0 +1,138 @@
+package controllers
+
+import (
+	"errors"
+	"net/http"
+	"rental-mobil/models"
+	"rental-mobil/repository"
+	"rental-mobil/services"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+)
+
+// GetAllMemberships mengambil semua tingkat membership dengan pagination
+func (h *Handler) GetAllMemberships(c echo.Context) error {
+	// Ambil parameter page dan limit dari query params
+	page, err := strconv.Atoi(c.QueryParam("page"))
+	if err != nil || page < 1 {
+		page = 1
+	}
+
+	limit, err := strconv.Atoi(c.QueryParam("limit"))
+	if err != nil || limit < 1 {
+		limit = 10
+	}
+
+	// Hitung offset
+	offset := (page - 1) * limit
+
+	memberships, err := h.Repos.Memberships.List(limit, offset)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"message": "Failed to fetch memberships"})
+	}
+
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"page":  page,
+		"limit": limit,
+		"data":  memberships,
+	})
+}
+
+// validateMembership memeriksa nama dan diskon membership
+func validateMembership(membership *models.Membership) string {
+	if membership.Name == "" {
+		return "Membership name is required"
+	}
+	if membership.Discount < 0 || membership.Discount > 100 {
+		return "Discount must be between 0 and 100"
+	}
+	if membership.MinRentals < 0 || membership.MinSpend < 0 || membership.MinDays < 0 {
+		return "Tier thresholds must not be negative"
+	}
+	return ""
+}
+
+// CreateMembership membuat tingkat membership baru
+func (h *Handler) CreateMembership(c echo.Context) error {
+	membership := new(models.Membership)
+	if err := c.Bind(membership); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"message": "Invalid input"})
+	}
+
+	if msg := validateMembership(membership); msg != "" {
+		return c.JSON(http.StatusBadRequest, map[string]string{"message": msg})
+	}
+
+	if err := h.Repos.Memberships.Create(membership); err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"message": "Failed to create membership"})
+	}
+
+	return c.JSON(http.StatusCreated, map[string]string{"message": "Membership created successfully"})
+}
+
+// UpdateMembership memperbarui tingkat membership
+func (h *Handler) UpdateMembership(c echo.Context) error {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"message": "Invalid membership ID"})
+	}
+	membership := new(models.Membership)
+	if err := c.Bind(membership); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"message": "Invalid input"})
+	}
+	membership.ID = id
+
+	if msg := validateMembership(membership); msg != "" {
+		return c.JSON(http.StatusBadRequest, map[string]string{"message": msg})
+	}
+
+	err = h.Repos.Memberships.Update(membership)
+	if errors.Is(err, repository.ErrNotFound) {
+		return c.JSON(http.StatusNotFound, map[string]string{"message": "Membership not found"})
+	}
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"message": "Failed to update membership"})
+	}
+
+	return c.JSON(http.StatusOK, map[string]string{"message": "Membership updated successfully"})
+}
+
+// DeleteMembership menghapus tingkat membership yang tidak lagi dipakai pelanggan
+func (h *Handler) DeleteMembership(c echo.Context) error {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"message": "Invalid membership ID"})
+	}
+
+	// Tolak jika masih ada pelanggan dengan membership ini
+	err = h.Repos.Memberships.Delete(id)
+	switch {
+	case errors.Is(err, repository.ErrInUse):
+		return c.JSON(http.StatusConflict, map[string]string{"message": "Membership is still assigned to customers"})
+	case errors.Is(err, repository.ErrNotFound):
+		return c.JSON(http.StatusNotFound, map[string]string{"message": "Membership not found"})
+	case err != nil:
+		return c.JSON(http.StatusInternalServerError, map[string]string{"message": "Failed to delete membership"})
+	}
+
+	return c.JSON(http.StatusOK, map[string]string{"message": "Membership deleted successfully"})
+}
+
+// EvaluateMembershipTiers menjalankan aturan kenaikan/penurunan tingkat
+// membership. Dengan dry_run=true hanya melaporkan perubahan tanpa menyimpan.
+func (h *Handler) EvaluateMembershipTiers(c echo.Context) error {
+	dryRun, _ := strconv.ParseBool(c.QueryParam("dry_run"))
+
+	changes, err := services.EvaluateMembershipTiers(h.Repos.Memberships, h.Repos.Customers, dryRun)
+	if err != nil {
+		c.Logger().Error("Error evaluating membership tiers:", err)
+		return c.JSON(http.StatusInternalServerError, map[string]string{"message": "Failed to evaluate membership tiers"})
+	}
+
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"dry_run": dryRun,
+		"total":   len(changes),
+		"data":    changes,
+	})
+}
@@ -0,0 +1,144 @@
+package controllers
+
+import (
+	"database/sql"
+	"net/http"
+	"rental-mobil/models"
+	"rental-mobil/services"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+)
+
+var paymentTypes = map[string]bool{
+	models.PaymentTypePayment: true,
+	models.PaymentTypeDeposit: true,
+	models.PaymentTypeRefund:  true,
+}
+
+var paymentMethods = map[string]bool{
+	models.PaymentMethodCash:         true,
+	models.PaymentMethodBankTransfer: true,
+	models.PaymentMethodQRIS:         true,
+}
+
+// GetBookingPayments mengambil semua pembayaran booking beserta ringkasan saldo
+func (h *Handler) GetBookingPayments(c echo.Context) error {
+	bookingID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"message": "Invalid booking ID"})
+	}
+
+	var count int
+	err = h.DB.Get(&count, `SELECT COUNT(*) FROM bookings WHERE id = $1 AND deleted_at IS NULL`, bookingID)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"message": "Failed to fetch booking"})
+	}
+	if count == 0 {
+		return c.JSON(http.StatusNotFound, map[string]string{"message": "Booking not found"})
+	}
+
+	payments := []models.Payment{}
+	query := `SELECT id, booking_id, type, method, amount, reference, note, paid_at
+        FROM payments WHERE booking_id = $1 ORDER BY paid_at, id`
+	err = h.DB.Select(&payments, query, bookingID)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"message": "Failed to fetch payments"})
+	}
+
+	balance, err := services.GetBookingBalance(h.DB, bookingID)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"message": "Failed to calculate balance"})
+	}
+
+	deposit, err := services.GetDepositSummary(h.DB, bookingID)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"message": "Failed to calculate deposit"})
+	}
+
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"booking_id": bookingID,
+		"balance":    balance,
+		"deposit":    deposit,
+		"data":       payments,
+	})
+}
+
+// CreateBookingPayment mencatat pembayaran, uang muka atau refund booking
+func (h *Handler) CreateBookingPayment(c echo.Context) error {
+	bookingID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"message": "Invalid booking ID"})
+	}
+
+	payment := new(models.Payment)
+	if err := c.Bind(payment); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"message": "Invalid input"})
+	}
+
+	// Validasi jenis, metode dan nominal pembayaran
+	if payment.Type == "" {
+		payment.Type = models.PaymentTypePayment
+	}
+	if !paymentTypes[payment.Type] {
+		return c.JSON(http.StatusBadRequest, map[string]string{"message": "Payment type must be payment, deposit or refund"})
+	}
+	if !paymentMethods[payment.Method] {
+		return c.JSON(http.StatusBadRequest, map[string]string{"message": "Payment method must be cash, bank_transfer or qris"})
+	}
+	if payment.Amount <= 0 {
+		return c.JSON(http.StatusBadRequest, map[string]string{"message": "Amount must be greater than zero"})
+	}
+
+	tx, err := h.DB.Beginx()
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"message": "Failed to start transaction"})
+	}
+	defer tx.Rollback()
+
+	var status string
+	err = tx.Get(&status, `SELECT status FROM bookings WHERE id = $1 AND deleted_at IS NULL FOR UPDATE`, bookingID)
+	if err == sql.ErrNoRows {
+		return c.JSON(http.StatusNotFound, map[string]string{"message": "Booking not found"})
+	}
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"message": "Failed to fetch booking"})
+	}
+
+	balance, err := services.GetBookingBalance(tx, bookingID)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"message": "Failed to calculate balance"})
+	}
+
+	if payment.Type == models.PaymentTypeRefund {
+		// Refund tidak boleh melebihi uang yang sudah diterima
+		if payment.Amount > balance.AmountPaid {
+			return c.JSON(http.StatusBadRequest, map[string]string{"message": "Refund exceeds amount paid"})
+		}
+	} else if status == models.BookingStatusCancelled || status == models.BookingStatusNoShow {
+		return c.JSON(http.StatusConflict, map[string]string{"message": "Cannot accept payments for a " + status + " booking"})
+	}
+
+	insertQuery := `INSERT INTO payments (booking_id, type, method, amount, reference, note, paid_at)
+        VALUES ($1, $2, $3, $4, $5, $6, NOW()) RETURNING id, paid_at`
+	err = tx.QueryRowx(insertQuery, bookingID, payment.Type, payment.Method, payment.Amount, payment.Reference, payment.Note).Scan(&payment.ID, &payment.PaidAt)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"message": "Failed to record payment"})
+	}
+	payment.BookingID = bookingID
+
+	balance, err = services.GetBookingBalance(tx, bookingID)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"message": "Failed to calculate balance"})
+	}
+
+	if err = tx.Commit(); err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"message": "Failed to record payment"})
+	}
+
+	return c.JSON(http.StatusCreated, map[string]interface{}{
+		"message": "Payment recorded successfully",
+		"payment": payment,
+		"balance": balance,
+	})
+}
@@ -0,0 +1,317 @@
+package controllers
+
+import (
+	"database/sql"
+	"io"
+	"net/http"
+	"rental-mobil/config"
+	"rental-mobil/models"
+	"rental-mobil/services"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+)
+
+// CreateBookingCharge membuat tagihan online untuk booking melalui payment
+// gateway. Nominal default adalah sisa tagihan booking.
+func (h *Handler) CreateBookingCharge(c echo.Context) error {
+	bookingID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"message": "Invalid booking ID"})
+	}
+
+	var input struct {
+		Provider string       `json:"provider"`
+		Amount   models.Money `json:"amount"`
+	}
+	if err := c.Bind(&input); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"message": "Invalid input"})
+	}
+	if input.Provider == "" {
+		input.Provider = config.GetPaymentGateway()
+	}
+	if input.Provider == "" {
+		return c.JSON(http.StatusBadRequest, map[string]string{"message": "Payment provider is required"})
+	}
+	gateway, ok := services.GetPaymentGateway(input.Provider)
+	if !ok {
+		return c.JSON(http.StatusBadRequest, map[string]string{"message": "Unknown payment provider"})
+	}
+
+	var status string
+	err = h.DB.Get(&status, `SELECT status FROM bookings WHERE id = $1 AND deleted_at IS NULL`, bookingID)
+	if err == sql.ErrNoRows {
+		return c.JSON(http.StatusNotFound, map[string]string{"message": "Booking not found"})
+	}
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"message": "Failed to fetch booking"})
+	}
+	if status == models.BookingStatusCancelled || status == models.BookingStatusNoShow {
+		return c.JSON(http.StatusConflict, map[string]string{"message": "Cannot accept payments for a " + status + " booking"})
+	}
+
+	balance, err := services.GetBookingBalance(h.DB, bookingID)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"message": "Failed to calculate balance"})
+	}
+	if input.Amount == 0 {
+		input.Amount = balance.BalanceDue
+	}
+	if input.Amount <= 0 {
+		return c.JSON(http.StatusBadRequest, map[string]string{"message": "Amount must be greater than zero"})
+	}
+
+	charge, err := gateway.CreateCharge(services.ChargeRequest{
+		BookingID:   bookingID,
+		Amount:      input.Amount,
+		Description: "Booking #" + strconv.Itoa(bookingID),
+	})
+	if err != nil {
+		c.Logger().Error("Error creating charge:", err)
+		return c.JSON(http.StatusBadGateway, map[string]string{"message": "Failed to create charge"})
+	}
+
+	paymentCharge := models.PaymentCharge{
+		BookingID:   bookingID,
+		Provider:    gateway.Name(),
+		ExternalID:  charge.ExternalID,
+		Amount:      input.Amount,
+		Status:      models.ChargeStatusPending,
+		CheckoutURL: charge.CheckoutURL,
+	}
+	insertQuery := `INSERT INTO payment_charges (booking_id, provider, external_id, amount, status, checkout_url, created_at, updated_at)
+        VALUES ($1, $2, $3, $4, $5, $6, NOW(), NOW()) RETURNING id, created_at, updated_at`
+	err = h.DB.QueryRowx(insertQuery, paymentCharge.BookingID, paymentCharge.Provider, paymentCharge.ExternalID,
+		paymentCharge.Amount, paymentCharge.Status, paymentCharge.CheckoutURL).Scan(&paymentCharge.ID, &paymentCharge.CreatedAt, &paymentCharge.UpdatedAt)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"message": "Failed to save charge"})
+	}
+
+	return c.JSON(http.StatusCreated, paymentCharge)
+}
+
+// PaymentWebhook menerima callback dari payment gateway, memverifikasi tanda
+// tangannya lalu memperbarui status tagihan
+func (h *Handler) PaymentWebhook(c echo.Context) error {
+	gateway, ok := services.GetPaymentGateway(c.Param("provider"))
+	if !ok {
+		return c.JSON(http.StatusNotFound, map[string]string{"message": "Unknown payment provider"})
+	}
+
+	body, err := io.ReadAll(c.Request().Body)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"message": "Invalid input"})
+	}
+
+	status, msg := h.processGatewayCallback(gateway, body, c.Request().Header)
+	return c.JSON(status, map[string]string{"message": msg})
+}
+
+// SimulateFakePayment mensimulasikan pelanggan membayar tagihan pada gateway
+// palsu. Callback bertanda tangan diproses seperti webhook sungguhan.
+func (h *Handler) SimulateFakePayment(c echo.Context) error {
+	gateway, ok := services.GetPaymentGateway(services.FakeGatewayName)
+	if !ok {
+		return c.JSON(http.StatusNotFound, map[string]string{"message": "Fake payment gateway is not enabled"})
+	}
+	fake, ok := gateway.(*services.FakeGateway)
+	if !ok {
+		return c.JSON(http.StatusNotFound, map[string]string{"message": "Fake payment gateway is not enabled"})
+	}
+
+	body, header, err := fake.SimulatePayment(c.Param("id"))
+	if err != nil {
+		return c.JSON(http.StatusNotFound, map[string]string{"message": "Charge not found"})
+	}
+
+	status, msg := h.processGatewayCallback(gateway, body, header)
+	return c.JSON(status, map[string]string{"message": msg})
+}
+
+// processGatewayCallback memproses callback secara idempoten: callback yang
+// sama boleh diterima berkali-kali tanpa mencatat pembayaran ganda
+func (h *Handler) processGatewayCallback(gateway services.PaymentGateway, body []byte, header http.Header) (int, string) {
+	event, err := gateway.ParseCallback(body, header)
+	if err == services.ErrInvalidSignature {
+		return http.StatusUnauthorized, "Invalid signature"
+	}
+	if err != nil {
+		return http.StatusBadRequest, "Invalid callback payload"
+	}
+
+	tx, err := h.DB.Beginx()
+	if err != nil {
+		return http.StatusInternalServerError, "Failed to start transaction"
+	}
+	defer tx.Rollback()
+
+	var charge models.PaymentCharge
+	query := `SELECT id, booking_id, provider, external_id, amount, refunded_amount, status, checkout_url, payment_id, created_at, updated_at
+        FROM payment_charges WHERE provider = $1 AND external_id = $2 FOR UPDATE`
+	err = tx.Get(&charge, query, gateway.Name(), event.ExternalID)
+	if err == sql.ErrNoRows {
+		return http.StatusNotFound, "Charge not found"
+	}
+	if err != nil {
+		return http.StatusInternalServerError, "Failed to fetch charge"
+	}
+
+	// Callback ulang atau callback yang datang terlambat cukup diakui saja
+	if charge.Status == event.Status {
+		return http.StatusOK, "Callback already processed"
+	}
+	if charge.Status != models.ChargeStatusPending {
+		return http.StatusOK, "Callback ignored for " + charge.Status + " charge"
+	}
+
+	switch event.Status {
+	case models.ChargeStatusPaid:
+		amount := event.Amount
+		if amount <= 0 {
+			amount = charge.Amount
+		}
+		method := event.Method
+		if !paymentMethods[method] {
+			method = models.PaymentMethodBankTransfer
+		}
+
+		insertQuery := `INSERT INTO payments (booking_id, type, method, amount, reference, note, paid_at)
+            VALUES ($1, $2, $3, $4, $5, $6, NOW()) RETURNING id`
+		err = tx.Get(&charge.PaymentID, insertQuery, charge.BookingID, models.PaymentTypePayment, method, amount,
+			event.Reference, "Online payment via "+gateway.Name())
+		if err != nil {
+			return http.StatusInternalServerError, "Failed to record payment"
+		}
+	case models.ChargeStatusFailed:
+	default:
+		return http.StatusBadRequest, "Unsupported charge status"
+	}
+
+	updateQuery := `UPDATE payment_charges SET status = $1, payment_id = $2, updated_at = NOW() WHERE id = $3`
+	if _, err := tx.Exec(updateQuery, event.Status, charge.PaymentID, charge.ID); err != nil {
+		return http.StatusInternalServerError, "Failed to update charge"
+	}
+
+	if err := tx.Commit(); err != nil {
+		return http.StatusInternalServerError, "Failed to update charge"
+	}
+
+	return http.StatusOK, "Callback processed"
+}
+
+// RefundCharge mengembalikan dana tagihan online yang sudah dibayar melalui
+// payment gateway yang sama dan mencatatnya sebagai refund. Refund dicatat
+// lebih dulu sebelum gateway dipanggil, lalu dibatalkan jika gateway gagal,
+// sehingga dana tidak pernah keluar tanpa catatan. Refund sebagian boleh
+// dilakukan berkali-kali sampai total refund sama dengan nominal tagihan.
+func (h *Handler) RefundCharge(c echo.Context) error {
+	chargeID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"message": "Invalid charge ID"})
+	}
+
+	var input struct {
+		Amount models.Money `json:"amount"`
+		Note   string       `json:"note"`
+	}
+	if err := c.Bind(&input); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"message": "Invalid input"})
+	}
+
+	tx, err := h.DB.Beginx()
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"message": "Failed to start transaction"})
+	}
+	defer tx.Rollback()
+
+	var charge models.PaymentCharge
+	query := `SELECT id, booking_id, provider, external_id, amount, refunded_amount, status, checkout_url, payment_id, created_at, updated_at
+        FROM payment_charges WHERE id = $1 FOR UPDATE`
+	err = tx.Get(&charge, query, chargeID)
+	if err == sql.ErrNoRows {
+		return c.JSON(http.StatusNotFound, map[string]string{"message": "Charge not found"})
+	}
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"message": "Failed to fetch charge"})
+	}
+	if charge.Status != models.ChargeStatusPaid {
+		return c.JSON(http.StatusConflict, map[string]string{"message": "Only paid charges can be refunded"})
+	}
+
+	remaining := charge.Amount - charge.RefundedAmount
+	if input.Amount == 0 {
+		input.Amount = remaining
+	}
+	if input.Amount <= 0 || input.Amount > remaining {
+		return c.JSON(http.StatusBadRequest, map[string]string{"message": "Refund amount must be between 1 and the amount not yet refunded"})
+	}
+
+	gateway, ok := services.GetPaymentGateway(charge.Provider)
+	if !ok {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"message": "Payment provider is not configured"})
+	}
+
+	// Catat refund dan tambah total refund tagihan sebelum memanggil gateway
+	var refundID int
+	insertQuery := `INSERT INTO payments (booking_id, type, method, amount, reference, note, paid_at)
+        SELECT booking_id, $1, method, $2, '', $3, NOW() FROM payments WHERE id = $4 RETURNING id`
+	err = tx.Get(&refundID, insertQuery, models.PaymentTypeRefund, input.Amount, input.Note, charge.PaymentID)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"message": "Failed to record refund"})
+	}
+
+	status := models.ChargeStatusPaid
+	if charge.RefundedAmount+input.Amount == charge.Amount {
+		status = models.ChargeStatusRefunded
+	}
+	updateQuery := `UPDATE payment_charges SET refunded_amount = refunded_amount + $1, status = $2, updated_at = NOW() WHERE id = $3`
+	if _, err := tx.Exec(updateQuery, input.Amount, status, charge.ID); err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"message": "Failed to update charge"})
+	}
+
+	if err := tx.Commit(); err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"message": "Failed to record refund"})
+	}
+
+	result, err := gateway.Refund(charge.ExternalID, input.Amount)
+	if err != nil {
+		c.Logger().Error("Error refunding charge:", err)
+		if err := h.revertChargeRefund(charge.ID, refundID, input.Amount); err != nil {
+			c.Logger().Error("Error reverting refund record:", err)
+		}
+		return c.JSON(http.StatusBadGateway, map[string]string{"message": "Failed to refund charge"})
+	}
+
+	// Dana sudah keluar dan refund sudah tercatat; kegagalan menyimpan
+	// referensi gateway cukup dicatat di log
+	_, err = h.DB.Exec(`UPDATE payments SET reference = $1 WHERE id = $2`, result.Reference, refundID)
+	if err != nil {
+		c.Logger().Error("Error saving refund reference:", err)
+	}
+
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"message":   "Charge refunded successfully",
+		"amount":    input.Amount,
+		"reference": result.Reference,
+	})
+}
+
+// revertChargeRefund menghapus catatan refund yang gagal diproses gateway
+// dan mengurangi kembali total refund tagihan
+func (h *Handler) revertChargeRefund(chargeID, refundID int, amount models.Money) error {
+	tx, err := h.DB.Beginx()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec(`DELETE FROM payments WHERE id = $1`, refundID); err != nil {
+		return err
+	}
+	updateQuery := `UPDATE payment_charges SET refunded_amount = refunded_amount - $1, status = $2, updated_at = NOW() WHERE id = $3`
+	if _, err := tx.Exec(updateQuery, amount, models.ChargeStatusPaid, chargeID); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
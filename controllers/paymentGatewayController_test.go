@@ -0,0 +1,223 @@
+package controllers_test
+
+import (
+	"bytes"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"rental-mobil/controllers"
+	"rental-mobil/models"
+	"rental-mobil/repository"
+	"rental-mobil/services"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/labstack/echo/v4"
+)
+
+// chargeDB adalah driver database/sql minimal yang hanya memahami query
+// pemrosesan callback: satu baris payment_charges dan hitungan payments
+type chargeDB struct {
+	mu        sync.Mutex
+	charge    models.PaymentCharge
+	paymentID int64
+	payments  int
+}
+
+type chargeConn struct{ db *chargeDB }
+
+func (c *chargeConn) Prepare(query string) (driver.Stmt, error) {
+	return &chargeStmt{db: c.db, query: query}, nil
+}
+func (c *chargeConn) Close() error              { return nil }
+func (c *chargeConn) Begin() (driver.Tx, error) { return c, nil }
+func (c *chargeConn) Commit() error             { return nil }
+func (c *chargeConn) Rollback() error           { return nil }
+
+type chargeStmt struct {
+	db    *chargeDB
+	query string
+}
+
+func (s *chargeStmt) Close() error  { return nil }
+func (s *chargeStmt) NumInput() int { return -1 }
+
+func (s *chargeStmt) Exec(args []driver.Value) (driver.Result, error) {
+	s.db.mu.Lock()
+	defer s.db.mu.Unlock()
+
+	if !strings.HasPrefix(s.query, "UPDATE payment_charges SET status") {
+		return nil, errors.New("unexpected exec: " + s.query)
+	}
+	s.db.charge.Status = args[0].(string)
+	if id, ok := args[1].(int64); ok {
+		paymentID := int(id)
+		s.db.charge.PaymentID = &paymentID
+	}
+	return driver.RowsAffected(1), nil
+}
+
+func (s *chargeStmt) Query(args []driver.Value) (driver.Rows, error) {
+	s.db.mu.Lock()
+	defer s.db.mu.Unlock()
+
+	switch {
+	case strings.Contains(s.query, "FROM payment_charges WHERE provider"):
+		charge := s.db.charge
+		var paymentID driver.Value
+		if charge.PaymentID != nil {
+			paymentID = int64(*charge.PaymentID)
+		}
+		return &chargeRows{
+			columns: []string{"id", "booking_id", "provider", "external_id", "amount", "refunded_amount", "status", "checkout_url", "payment_id", "created_at", "updated_at"},
+			values: [][]driver.Value{{int64(charge.ID), int64(charge.BookingID), charge.Provider, charge.ExternalID, int64(charge.Amount),
+				int64(charge.RefundedAmount), charge.Status, charge.CheckoutURL, paymentID, charge.CreatedAt, charge.UpdatedAt}},
+		}, nil
+	case strings.HasPrefix(s.query, "INSERT INTO payments"):
+		s.db.payments++
+		s.db.paymentID++
+		return &chargeRows{columns: []string{"id"}, values: [][]driver.Value{{s.db.paymentID}}}, nil
+	}
+	return nil, errors.New("unexpected query: " + s.query)
+}
+
+type chargeRows struct {
+	columns []string
+	values  [][]driver.Value
+}
+
+func (r *chargeRows) Columns() []string { return r.columns }
+func (r *chargeRows) Close() error      { return nil }
+
+func (r *chargeRows) Next(dest []driver.Value) error {
+	if len(r.values) == 0 {
+		return io.EOF
+	}
+	copy(dest, r.values[0])
+	r.values = r.values[1:]
+	return nil
+}
+
+var registerChargeDB sync.Once
+
+// newChargeHandler menyiapkan handler dengan gateway palsu dan satu tagihan
+// pending di database tiruan
+func newChargeHandler(t *testing.T) (*controllers.Handler, *services.FakeGateway, *chargeDB) {
+	t.Helper()
+
+	fake := services.NewFakeGateway("secret", "http://localhost:8080")
+	services.RegisterPaymentGateway(fake)
+	charge, err := fake.CreateCharge(services.ChargeRequest{BookingID: 1, Amount: 500000})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	state := &chargeDB{charge: models.PaymentCharge{
+		ID:         1,
+		BookingID:  1,
+		Provider:   fake.Name(),
+		ExternalID: charge.ExternalID,
+		Amount:     500000,
+		Status:     models.ChargeStatusPending,
+		CreatedAt:  time.Now(),
+		UpdatedAt:  time.Now(),
+	}}
+	registerChargeDB.Do(func() {
+		sql.Register("chargedb", &chargeDriver{})
+	})
+	chargeDrivers.Store(t.Name(), state)
+
+	db, err := sql.Open("chargedb", t.Name())
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { db.Close() })
+
+	return controllers.NewHandler(repository.NewMemory(), sqlx.NewDb(db, "postgres")), fake, state
+}
+
+// chargeDriver memilih state chargeDB berdasarkan DSN yaitu nama test
+type chargeDriver struct{}
+
+var chargeDrivers sync.Map
+
+func (chargeDriver) Open(name string) (driver.Conn, error) {
+	state, ok := chargeDrivers.Load(name)
+	if !ok {
+		return nil, errors.New("unknown chargedb " + name)
+	}
+	return &chargeConn{db: state.(*chargeDB)}, nil
+}
+
+// postWebhook mengirim callback ke PaymentWebhook
+func postWebhook(t *testing.T, h *controllers.Handler, provider string, body []byte, header http.Header) *httptest.ResponseRecorder {
+	t.Helper()
+
+	req := httptest.NewRequest(http.MethodPost, "/payments/webhook/"+provider, bytes.NewReader(body))
+	for key, values := range header {
+		req.Header[key] = values
+	}
+	rec := httptest.NewRecorder()
+	c := echo.New().NewContext(req, rec)
+	c.SetParamNames("provider")
+	c.SetParamValues(provider)
+	if err := h.PaymentWebhook(c); err != nil {
+		t.Fatal(err)
+	}
+	return rec
+}
+
+func TestPaymentWebhookIsIdempotent(t *testing.T) {
+	h, fake, state := newChargeHandler(t)
+
+	body, header, err := fake.SimulatePayment(state.charge.ExternalID)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for i := 0; i < 2; i++ {
+		rec := postWebhook(t, h, fake.Name(), body, header)
+		if rec.Code != http.StatusOK {
+			t.Fatalf("callback %d: expected 200, got %d: %s", i+1, rec.Code, rec.Body.String())
+		}
+	}
+
+	// Callback paid berikutnya dengan referensi berbeda tetap tidak dicatat ulang
+	body, header, err = fake.SimulatePayment(state.charge.ExternalID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rec := postWebhook(t, h, fake.Name(), body, header); rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+
+	if state.payments != 1 {
+		t.Errorf("expected 1 payment row, got %d", state.payments)
+	}
+	if state.charge.Status != models.ChargeStatusPaid {
+		t.Errorf("expected charge status %q, got %q", models.ChargeStatusPaid, state.charge.Status)
+	}
+}
+
+func TestPaymentWebhookRejectsInvalidSignature(t *testing.T) {
+	h, fake, state := newChargeHandler(t)
+
+	body, _, err := fake.SimulatePayment(state.charge.ExternalID)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rec := postWebhook(t, h, fake.Name(), body, http.Header{"X-Signature": {"00"}})
+	if rec.Code != http.StatusUnauthorized {
+		t.Fatalf("expected 401, got %d: %s", rec.Code, rec.Body.String())
+	}
+	if state.payments != 0 {
+		t.Errorf("expected no payment rows, got %d", state.payments)
+	}
+}
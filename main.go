@@ -1,8 +1,12 @@
 package main
 
 import (
+	"log"
 	"rental-mobil/config"
+	"rental-mobil/controllers"
+	"rental-mobil/repository"
 	"rental-mobil/routes"
+	"rental-mobil/services"
 
 	"github.com/labstack/echo/v4"
 )
@@ -11,13 +15,36 @@ func main() {
 	// Inisialisasi database
 	config.InitDB()
 
+	// Repository Postgres disuntikkan ke seluruh controller
+	repos := repository.NewPostgres(config.DB)
+	h := controllers.NewHandler(repos, config.DB)
+
 	// Inisialisasi Echo
 	e := echo.New()
 
-	// Daftarkan rute mobil dan pelanggan
-	routes.RegisterCustomerRoutes(e)
-	routes.RegisterCarRoutes(e)
-	routes.BookingRoutes(e)
+	// Daftarkan rute mobil, pelanggan, booking, jenis booking, supir, membership, dan pembayaran
+	routes.RegisterCustomerRoutes(e, h)
+	routes.RegisterCarRoutes(e, h)
+	routes.BookingRoutes(e, h)
+	routes.RegisterBookingTypeRoutes(e, h)
+	routes.RegisterDriverRoutes(e, h)
+	routes.RegisterMembershipRoutes(e, h)
+	routes.RegisterPaymentRoutes(e, h)
+	routes.RegisterAdminRoutes(e, h)
+
+	// Payment gateway palsu hanya untuk pengujian lokal dan wajib diaktifkan
+	// secara eksplisit beserta secret penandatangannya
+	if config.GetFakeGatewayEnabled() {
+		secret := config.GetFakeGatewaySecret()
+		if secret == "" {
+			log.Fatalf("FAKE_GATEWAY_SECRET is required when FAKE_GATEWAY_ENABLED is set")
+		}
+		services.RegisterPaymentGateway(services.NewFakeGateway(secret, config.GetBaseURL()))
+		routes.RegisterFakeGatewayRoutes(e, h)
+	}
+
+	// Evaluasi tingkat membership secara berkala
+	services.StartMembershipTierScheduler(repos.Memberships, repos.Customers, config.GetMembershipTierInterval())
 
 	// Jalankan server
 	e.Logger.Fatal(e.Start(":5000"))
@@ -0,0 +1,9 @@
+-- Booking lepas kunci tanpa supir dan penanda jenis booking yang wajib supir
+
+ALTER TABLE bookings ALTER COLUMN driver_id DROP NOT NULL;
+
+-- Booking lama tanpa supir tersimpan dengan driver_id 0
+UPDATE bookings SET driver_id = NULL WHERE driver_id = 0;
+
+-- Jenis booking lama selalu memakai supir
+ALTER TABLE booking_type ADD COLUMN IF NOT EXISTS requires_driver BOOLEAN NOT NULL DEFAULT TRUE;
@@ -0,0 +1,24 @@
+-- Ganti kolom finished dengan status booking beserta waktu setiap perpindahan
+
+ALTER TABLE bookings
+    ADD COLUMN IF NOT EXISTS status VARCHAR(16) NOT NULL DEFAULT 'reserved',
+    ADD COLUMN IF NOT EXISTS reserved_at TIMESTAMP,
+    ADD COLUMN IF NOT EXISTS picked_up_at TIMESTAMP,
+    ADD COLUMN IF NOT EXISTS returned_at TIMESTAMP,
+    ADD COLUMN IF NOT EXISTS cancelled_at TIMESTAMP,
+    ADD COLUMN IF NOT EXISTS no_show_at TIMESTAMP;
+
+-- Booking yang sudah selesai menjadi returned, sisanya tetap reserved agar
+-- tetap dihitung oleh pengecekan stok mobil dan jadwal supir
+DO $$
+BEGIN
+    IF EXISTS (
+        SELECT 1 FROM information_schema.columns
+        WHERE table_name = 'bookings' AND column_name = 'finished'
+    ) THEN
+        UPDATE bookings SET status = CASE WHEN finished THEN 'returned' ELSE 'reserved' END;
+        ALTER TABLE bookings DROP COLUMN finished;
+    END IF;
+END $$;
+
+CREATE INDEX IF NOT EXISTS bookings_car_id_status_idx ON bookings (car_id, status);
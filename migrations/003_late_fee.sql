@@ -0,0 +1,5 @@
+-- Tanggal pengembalian sebenarnya dan denda keterlambatan per booking
+
+ALTER TABLE bookings
+    ADD COLUMN IF NOT EXISTS return_date DATE,
+    ADD COLUMN IF NOT EXISTS late_fee DOUBLE PRECISION NOT NULL DEFAULT 0;
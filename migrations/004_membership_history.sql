@@ -0,0 +1,12 @@
+-- Riwayat perubahan membership pelanggan
+
+CREATE TABLE IF NOT EXISTS membership_history (
+    id                SERIAL PRIMARY KEY,
+    customer_id       INT NOT NULL REFERENCES customers (id) ON DELETE CASCADE,
+    old_membership_id INT REFERENCES membership (id) ON DELETE SET NULL,
+    new_membership_id INT REFERENCES membership (id) ON DELETE SET NULL,
+    effective_date    DATE NOT NULL,
+    created_at        TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS membership_history_customer_id_idx ON membership_history (customer_id);
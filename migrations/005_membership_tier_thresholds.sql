@@ -0,0 +1,7 @@
+-- Syarat kenaikan tingkat membership dari riwayat sewa 12 bulan terakhir.
+-- Tingkat dengan semua syarat 0 tidak ikut evaluasi otomatis.
+
+ALTER TABLE membership
+    ADD COLUMN IF NOT EXISTS min_rentals INT NOT NULL DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS min_spend DOUBLE PRECISION NOT NULL DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS min_days INT NOT NULL DEFAULT 0;
@@ -0,0 +1,5 @@
+-- Aturan harga per jenis booking: biaya tambahan dan minimal durasi sewa
+
+ALTER TABLE booking_type
+    ADD COLUMN IF NOT EXISTS surcharge_percentage NUMERIC(5, 2) NOT NULL DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS min_days INT NOT NULL DEFAULT 0;
@@ -0,0 +1,21 @@
+-- Simpan semua nominal uang sebagai rupiah utuh (NUMERIC tanpa pecahan)
+-- agar tidak ada lagi pembulatan float di database.
+
+ALTER TABLE cars
+    ALTER COLUMN daily_rent TYPE NUMERIC(14, 0) USING ROUND(daily_rent::numeric);
+
+ALTER TABLE driver
+    ALTER COLUMN daily_cost TYPE NUMERIC(14, 0) USING ROUND(daily_cost::numeric);
+
+ALTER TABLE bookings
+    ALTER COLUMN total_cost TYPE NUMERIC(14, 0) USING ROUND(total_cost::numeric),
+    ALTER COLUMN total_driver_cost TYPE NUMERIC(14, 0) USING ROUND(total_driver_cost::numeric),
+    ALTER COLUMN late_fee TYPE NUMERIC(14, 0) USING ROUND(late_fee::numeric),
+    ALTER COLUMN discount TYPE NUMERIC(5, 2) USING discount::numeric;
+
+ALTER TABLE driver_incentive
+    ALTER COLUMN incentive TYPE NUMERIC(14, 0) USING ROUND(incentive::numeric);
+
+ALTER TABLE membership
+    ALTER COLUMN discount TYPE NUMERIC(5, 2) USING discount::numeric,
+    ALTER COLUMN min_spend TYPE NUMERIC(14, 0) USING ROUND(min_spend::numeric);
@@ -0,0 +1,22 @@
+-- Invoice bernomor per booking beserta baris rinciannya
+
+CREATE TABLE IF NOT EXISTS invoices (
+    id             SERIAL PRIMARY KEY,
+    booking_id     INT NOT NULL UNIQUE REFERENCES bookings (id) ON DELETE CASCADE,
+    number         VARCHAR(32) NOT NULL,
+    issued_at      TIMESTAMP NOT NULL DEFAULT NOW(),
+    tax_percentage NUMERIC(5, 2) NOT NULL DEFAULT 0,
+    subtotal       NUMERIC(14, 0) NOT NULL DEFAULT 0,
+    tax            NUMERIC(14, 0) NOT NULL DEFAULT 0,
+    total          NUMERIC(14, 0) NOT NULL DEFAULT 0
+);
+
+CREATE TABLE IF NOT EXISTS invoice_items (
+    id          SERIAL PRIMARY KEY,
+    invoice_id  INT NOT NULL REFERENCES invoices (id) ON DELETE CASCADE,
+    kind        VARCHAR(16) NOT NULL,
+    description VARCHAR(255) NOT NULL,
+    quantity    INT NOT NULL DEFAULT 1,
+    unit_price  NUMERIC(14, 0) NOT NULL DEFAULT 0,
+    amount      NUMERIC(14, 0) NOT NULL DEFAULT 0
+);
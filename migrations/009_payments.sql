@@ -0,0 +1,14 @@
+-- Pembayaran, uang muka dan refund per booking
+
+CREATE TABLE IF NOT EXISTS payments (
+    id         SERIAL PRIMARY KEY,
+    booking_id INT NOT NULL REFERENCES bookings (id) ON DELETE CASCADE,
+    type       VARCHAR(16) NOT NULL,
+    method     VARCHAR(16) NOT NULL,
+    amount     NUMERIC(14, 0) NOT NULL CHECK (amount > 0),
+    reference  VARCHAR(64) NOT NULL DEFAULT '',
+    note       TEXT NOT NULL DEFAULT '',
+    paid_at    TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS payments_booking_id_idx ON payments (booking_id);
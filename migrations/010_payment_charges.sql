@@ -0,0 +1,15 @@
+-- Tagihan online di payment gateway
+
+CREATE TABLE IF NOT EXISTS payment_charges (
+    id           SERIAL PRIMARY KEY,
+    booking_id   INT NOT NULL REFERENCES bookings (id) ON DELETE CASCADE,
+    provider     VARCHAR(32) NOT NULL,
+    external_id  VARCHAR(64) NOT NULL,
+    amount       NUMERIC(14, 0) NOT NULL CHECK (amount > 0),
+    status       VARCHAR(16) NOT NULL DEFAULT 'pending',
+    checkout_url TEXT NOT NULL DEFAULT '',
+    payment_id   INT REFERENCES payments (id),
+    created_at   TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at   TIMESTAMP NOT NULL DEFAULT NOW(),
+    UNIQUE (provider, external_id)
+);
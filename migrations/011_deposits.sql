@@ -0,0 +1,15 @@
+-- Uang jaminan per mobil dan transaksi uang jaminan per booking
+
+ALTER TABLE cars ADD COLUMN IF NOT EXISTS deposit_amount NUMERIC(14, 0) NOT NULL DEFAULT 0;
+ALTER TABLE bookings ADD COLUMN IF NOT EXISTS deposit_amount NUMERIC(14, 0) NOT NULL DEFAULT 0;
+
+CREATE TABLE IF NOT EXISTS deposit_transactions (
+    id         SERIAL PRIMARY KEY,
+    booking_id INT NOT NULL REFERENCES bookings (id) ON DELETE CASCADE,
+    type       VARCHAR(16) NOT NULL,
+    category   VARCHAR(16) NOT NULL DEFAULT '',
+    method     VARCHAR(16) NOT NULL DEFAULT '',
+    amount     NUMERIC(14, 0) NOT NULL CHECK (amount > 0),
+    reason     TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
@@ -0,0 +1,7 @@
+-- Kebijakan refund per jenis booking dan catatan pembatalan booking
+
+ALTER TABLE booking_type ADD COLUMN IF NOT EXISTS cancellation_policy JSONB NOT NULL DEFAULT '[]';
+
+ALTER TABLE bookings
+    ADD COLUMN IF NOT EXISTS cancellation_fee NUMERIC(14, 0) NOT NULL DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS cancellation_reason TEXT;
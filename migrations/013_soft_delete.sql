@@ -0,0 +1,5 @@
+-- Soft delete untuk mobil, pelanggan dan booking
+
+ALTER TABLE cars ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMP;
+ALTER TABLE customers ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMP;
+ALTER TABLE bookings ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMP;
@@ -0,0 +1,5 @@
+-- Total refund per tagihan online agar refund sebagian bisa dilakukan berkali-kali
+
+ALTER TABLE payment_charges ADD COLUMN IF NOT EXISTS refunded_amount NUMERIC(14, 0) NOT NULL DEFAULT 0;
+
+UPDATE payment_charges SET refunded_amount = amount WHERE status = 'refunded' AND refunded_amount = 0;
@@ -1,15 +1,71 @@
 package models
 
+import "time"
+
+// Status booking
+const (
+	BookingStatusReserved  = "reserved"  // Sudah dipesan, mobil belum diambil
+	BookingStatusPickedUp  = "picked_up" // Mobil sedang disewa
+	BookingStatusReturned  = "returned"  // Mobil sudah dikembalikan
+	BookingStatusCancelled = "cancelled" // Booking dibatalkan
+	BookingStatusNoShow    = "no_show"   // Pelanggan tidak datang
+)
+
+// bookingTransitions mendefinisikan perpindahan status booking yang sah
+var bookingTransitions = map[string][]string{
+	BookingStatusReserved: {BookingStatusPickedUp, BookingStatusCancelled, BookingStatusNoShow},
+	BookingStatusPickedUp: {BookingStatusReturned},
+}
+
+// CanTransitionBooking memeriksa apakah status from boleh berpindah ke to
+func CanTransitionBooking(from, to string) bool {
+	for _, next := range bookingTransitions[from] {
+		if next == to {
+			return true
+		}
+	}
+	return false
+}
+
+// IsActiveBookingStatus true jika booking masih memakai stok mobil dan supir
+func IsActiveBookingStatus(status string) bool {
+	return status == BookingStatusReserved || status == BookingStatusPickedUp
+}
+
 type Booking struct {
-	ID              int     `json:"id" db:"id"`
-	CustomerID      int     `json:"customer_id" db:"customer_id"`
-	CarID           int     `json:"car_id" db:"car_id"`
-	StartRent       string  `json:"start_rent" db:"start_rent"` // Tanggal mulai sewa
-	EndRent         string  `json:"end_rent" db:"end_rent"`     // Tanggal selesai sewa
-	TotalCost       float64 `json:"total_cost" db:"total_cost"` // Total biaya sewa
-	Finished        bool    `json:"finished" db:"finished"`     // Status selesai
-	Discount        float64 `json:"discount" db:"discount"`     // Diskon yang diterapkan
-	BookingTypeID   int     `json:"booking_type_id" db:"booking_type_id"` // ID jenis booking
-	DriverID        int     `json:"driver_id" db:"driver_id"`         // ID supir
-	TotalDriverCost float64 `json:"total_driver_cost" db:"total_driver_cost"` // Biaya supir
+	ID              int        `json:"id" db:"id"`
+	CustomerID      int        `json:"customer_id" db:"customer_id"`
+	CarID           int        `json:"car_id" db:"car_id"`
+	StartRent       string     `json:"start_rent" db:"start_rent"`               // Tanggal mulai sewa
+	EndRent         string     `json:"end_rent" db:"end_rent"`                   // Tanggal selesai sewa
+	TotalCost       Money      `json:"total_cost" db:"total_cost"`               // Total biaya sewa
+	Status          string     `json:"status" db:"status"`                       // Status booking
+	Discount        float64    `json:"discount" db:"discount"`                   // Diskon yang diterapkan
+	BookingTypeID   int        `json:"booking_type_id" db:"booking_type_id"`     // ID jenis booking
+	DriverID        *int       `json:"driver_id" db:"driver_id"`                 // ID supir, kosong untuk lepas kunci
+	TotalDriverCost Money      `json:"total_driver_cost" db:"total_driver_cost"` // Biaya supir
+	ReturnDate      *string    `json:"return_date" db:"return_date"`             // Tanggal mobil benar-benar dikembalikan
+	LateFee         Money      `json:"late_fee" db:"late_fee"`                   // Denda keterlambatan pengembalian
+	AmountPaid      Money      `json:"amount_paid" db:"amount_paid"`             // Dihitung dari tabel payments
+	BalanceDue      Money      `json:"balance_due" db:"balance_due"`             // Sisa tagihan yang belum dibayar
+	DepositAmount   Money      `json:"deposit_amount" db:"deposit_amount"`       // Uang jaminan yang harus ditahan
+	CancellationFee Money      `json:"cancellation_fee" db:"cancellation_fee"`   // Biaya pembatalan sesuai kebijakan refund, termasuk pajak
+	CancelReason    *string    `json:"cancellation_reason" db:"cancellation_reason"`
+	DeletedAt       *time.Time `json:"deleted_at,omitempty" db:"deleted_at"` // Terisi jika booking dihapus (soft delete)
+	ReservedAt      *time.Time `json:"reserved_at" db:"reserved_at"`         // Waktu booking dibuat
+	PickedUpAt      *time.Time `json:"picked_up_at" db:"picked_up_at"`       // Waktu mobil diambil
+	ReturnedAt      *time.Time `json:"returned_at" db:"returned_at"`         // Waktu mobil dikembalikan
+	CancelledAt     *time.Time `json:"cancelled_at" db:"cancelled_at"`       // Waktu booking dibatalkan
+	NoShowAt        *time.Time `json:"no_show_at" db:"no_show_at"`           // Waktu ditandai tidak datang
+}
+
+// BlockingBooking adalah booking yang belum selesai dan menghalangi
+// penghapusan mobil atau pelanggan
+type BlockingBooking struct {
+	ID         int    `json:"id" db:"id"`
+	CustomerID int    `json:"customer_id" db:"customer_id"`
+	CarID      int    `json:"car_id" db:"car_id"`
+	StartRent  string `json:"start_rent" db:"start_rent"`
+	EndRent    string `json:"end_rent" db:"end_rent"`
+	Status     string `json:"status" db:"status"`
 }
@@ -1,7 +1,11 @@
 package models
 
 type BookingType struct {
-	ID          int    `json:"id" db:"id"`
-	Name        string `json:"name" db:"name"`
-	Description string `json:"description" db:"description"`
+	ID                  int                `json:"id" db:"id"`
+	Name                string             `json:"name" db:"name"`
+	Description         string             `json:"description" db:"description"`
+	RequiresDriver      bool               `json:"requires_driver" db:"requires_driver"`           // true untuk "Car & Driver", false untuk "Car Only"
+	SurchargePercentage float64            `json:"surcharge_percentage" db:"surcharge_percentage"` // Biaya tambahan dari harga sewa
+	MinDays             int                `json:"min_days" db:"min_days"`                         // Minimal durasi sewa
+	CancellationPolicy  CancellationPolicy `json:"cancellation_policy" db:"cancellation_policy"`   // Tier refund pembatalan
 }
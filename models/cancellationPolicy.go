@@ -0,0 +1,66 @@
+package models
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"sort"
+)
+
+// CancellationTier menentukan persentase refund jika booking dibatalkan
+// paling lambat MinDaysBefore hari sebelum start_rent
+type CancellationTier struct {
+	MinDaysBefore    int     `json:"min_days_before"`
+	RefundPercentage float64 `json:"refund_percentage"`
+}
+
+// CancellationPolicy adalah daftar tier refund, disimpan sebagai JSONB
+type CancellationPolicy []CancellationTier
+
+// DefaultCancellationPolicy dipakai jika jenis booking tidak mengatur tier:
+// refund penuh 7 hari sebelumnya, 50% sehari sebelumnya, selain itu hangus
+var DefaultCancellationPolicy = CancellationPolicy{
+	{MinDaysBefore: 7, RefundPercentage: 100},
+	{MinDaysBefore: 1, RefundPercentage: 50},
+}
+
+// RefundPercentage mengembalikan persentase refund untuk pembatalan yang
+// dilakukan daysBefore hari sebelum start_rent
+func (p CancellationPolicy) RefundPercentage(daysBefore int) float64 {
+	tiers := p
+	if len(tiers) == 0 {
+		tiers = DefaultCancellationPolicy
+	}
+
+	sorted := append(CancellationPolicy(nil), tiers...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].MinDaysBefore > sorted[j].MinDaysBefore })
+	for _, tier := range sorted {
+		if daysBefore >= tier.MinDaysBefore {
+			return tier.RefundPercentage
+		}
+	}
+	return 0
+}
+
+// Value menyimpan kebijakan sebagai JSON
+func (p CancellationPolicy) Value() (driver.Value, error) {
+	if p == nil {
+		p = CancellationPolicy{}
+	}
+	return json.Marshal(p)
+}
+
+// Scan membaca kebijakan dari kolom JSONB
+func (p *CancellationPolicy) Scan(src interface{}) error {
+	switch v := src.(type) {
+	case nil:
+		*p = nil
+		return nil
+	case []byte:
+		return json.Unmarshal(v, p)
+	case string:
+		return json.Unmarshal([]byte(v), p)
+	default:
+		return fmt.Errorf("cannot scan %T into CancellationPolicy", src)
+	}
+}
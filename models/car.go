@@ -1,9 +1,12 @@
 package models
 
+import "time"
 
 type Car struct {
-	ID        int     `json:"id" db:"id"`
-	Name      string  `json:"name" db:"name"`
-	Stock     int     `json:"stock" db:"stock"`
-	DailyRent float64 `json:"daily_rent" db:"daily_rent"`
+	ID            int        `json:"id" db:"id"`
+	Name          string     `json:"name" db:"name"`
+	Stock         int        `json:"stock" db:"stock"`
+	DailyRent     Money      `json:"daily_rent" db:"daily_rent"`
+	DepositAmount Money      `json:"deposit_amount" db:"deposit_amount"`   // Uang jaminan yang ditahan saat pengambilan
+	DeletedAt     *time.Time `json:"deleted_at,omitempty" db:"deleted_at"` // Terisi jika mobil dihapus (soft delete)
 }
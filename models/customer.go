@@ -1,9 +1,12 @@
 package models
 
+import "time"
+
 type Customer struct {
-	ID           int    `json:"id" db:"id"`
-	Name         string `json:"name" db:"name"`
-	NIK          string `json:"nik" db:"nik"`
-	Phone        string `json:"phone_number" db:"phone"`
-	MembershipID *int   `json:"membership_id" db:"membership_id"`
+	ID           int        `json:"id" db:"id"`
+	Name         string     `json:"name" db:"name"`
+	NIK          string     `json:"nik" db:"nik"`
+	Phone        string     `json:"phone_number" db:"phone"`
+	MembershipID *int       `json:"membership_id" db:"membership_id"`
+	DeletedAt    *time.Time `json:"deleted_at,omitempty" db:"deleted_at"` // Terisi jika pelanggan dihapus (soft delete)
 }
@@ -0,0 +1,28 @@
+package models
+
+import "time"
+
+// Jenis transaksi uang jaminan
+const (
+	DepositHold      = "hold"      // Uang jaminan diterima saat pengambilan mobil
+	DepositDeduction = "deduction" // Potongan kerusakan, bahan bakar atau kebersihan
+	DepositRelease   = "release"   // Sisa uang jaminan dikembalikan ke pelanggan
+)
+
+// Kategori potongan uang jaminan
+const (
+	DeductionDamage   = "damage"
+	DeductionFuel     = "fuel"
+	DeductionCleaning = "cleaning"
+)
+
+type DepositTransaction struct {
+	ID        int       `json:"id" db:"id"`
+	BookingID int       `json:"booking_id" db:"booking_id"`
+	Type      string    `json:"type" db:"type"`         // hold, deduction atau release
+	Category  string    `json:"category" db:"category"` // Kategori potongan, kosong untuk hold dan release
+	Method    string    `json:"method" db:"method"`     // Metode pembayaran untuk hold dan release
+	Amount    Money     `json:"amount" db:"amount"`
+	Reason    string    `json:"reason" db:"reason"`
+	CreatedAt time.Time `json:"created_at" db:"created_at"`
+}
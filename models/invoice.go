@@ -0,0 +1,36 @@
+package models
+
+import "time"
+
+// Jenis baris invoice
+const (
+	InvoiceItemRent            = "rent"
+	InvoiceItemSurcharge       = "surcharge"
+	InvoiceItemDiscount        = "discount"
+	InvoiceItemDriver          = "driver"
+	InvoiceItemLateFee         = "late_fee"
+	InvoiceItemCancellationFee = "cancellation_fee"
+	InvoiceItemTax             = "tax"
+)
+
+type Invoice struct {
+	ID            int           `json:"id" db:"id"`
+	BookingID     int           `json:"booking_id" db:"booking_id"`
+	Number        string        `json:"number" db:"number"` // Nomor invoice, contoh INV-202610-000123
+	IssuedAt      time.Time     `json:"issued_at" db:"issued_at"`
+	TaxPercentage float64       `json:"tax_percentage" db:"tax_percentage"`
+	Subtotal      Money         `json:"subtotal" db:"subtotal"` // Jumlah semua baris sebelum pajak
+	Tax           Money         `json:"tax" db:"tax"`
+	Total         Money         `json:"total" db:"total"`
+	Items         []InvoiceItem `json:"items" db:"-"`
+}
+
+type InvoiceItem struct {
+	ID          int    `json:"id" db:"id"`
+	InvoiceID   int    `json:"invoice_id" db:"invoice_id"`
+	Kind        string `json:"kind" db:"kind"` // rent, surcharge, discount, driver, late_fee, cancellation_fee, tax
+	Description string `json:"description" db:"description"`
+	Quantity    int    `json:"quantity" db:"quantity"`
+	UnitPrice   Money  `json:"unit_price" db:"unit_price"`
+	Amount      Money  `json:"amount" db:"amount"` // Negatif untuk diskon
+}
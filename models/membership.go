@@ -1,7 +1,10 @@
 package models
 
 type Membership struct {
-	ID       int     `json:"id" db:"id"`
-	Name     string  `json:"membership_name" db:"name"` // Nama keanggotaan
-	Discount float64 `json:"discount" db:"discount"`   // Diskon yang diterapkan
+	ID         int     `json:"id" db:"id"`
+	Name       string  `json:"membership_name" db:"name"`    // Nama keanggotaan
+	Discount   float64 `json:"discount" db:"discount"`       // Diskon yang diterapkan
+	MinRentals int     `json:"min_rentals" db:"min_rentals"` // Minimal jumlah sewa selesai dalam 12 bulan
+	MinSpend   Money   `json:"min_spend" db:"min_spend"`     // Minimal total belanja dalam 12 bulan
+	MinDays    int     `json:"min_days" db:"min_days"`       // Minimal jumlah hari sewa dalam 12 bulan
 }
@@ -0,0 +1,12 @@
+package models
+
+import "time"
+
+type MembershipHistory struct {
+	ID              int       `json:"id" db:"id"`
+	CustomerID      int       `json:"customer_id" db:"customer_id"`
+	OldMembershipID *int      `json:"old_membership_id" db:"old_membership_id"` // Membership sebelumnya
+	NewMembershipID *int      `json:"new_membership_id" db:"new_membership_id"` // Membership baru
+	EffectiveDate   string    `json:"effective_date" db:"effective_date"`       // Tanggal mulai berlaku
+	CreatedAt       time.Time `json:"created_at" db:"created_at"`
+}
@@ -0,0 +1,132 @@
+package models
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// Money adalah nominal dalam rupiah utuh. Semua hasil perkalian dengan
+// persentase atau pengali dibulatkan ke rupiah terdekat (setengah menjauhi
+// nol) sehingga tidak ada pecahan sen yang tersimpan.
+type Money int64
+
+// RoundMoney membulatkan nilai float ke rupiah terdekat
+func RoundMoney(value float64) Money {
+	return Money(math.Round(value))
+}
+
+// Times mengalikan nominal dengan bilangan bulat, misalnya jumlah hari
+func (m Money) Times(n int) Money {
+	return m * Money(n)
+}
+
+// Percent menghitung percent persen dari nominal lalu membulatkannya
+func (m Money) Percent(percent float64) Money {
+	return RoundMoney(float64(m) * percent / 100)
+}
+
+// Multiply mengalikan nominal dengan faktor lalu membulatkannya
+func (m Money) Multiply(factor float64) Money {
+	return RoundMoney(float64(m) * factor)
+}
+
+// Value menyimpan Money ke kolom NUMERIC sebagai bilangan bulat
+func (m Money) Value() (driver.Value, error) {
+	return int64(m), nil
+}
+
+// Scan membaca Money dari kolom NUMERIC, integer maupun float
+func (m *Money) Scan(src interface{}) error {
+	switch v := src.(type) {
+	case nil:
+		*m = 0
+	case int64:
+		*m = Money(v)
+	case float64:
+		*m = RoundMoney(v)
+	case []byte:
+		return m.parse(string(v))
+	case string:
+		return m.parse(v)
+	default:
+		return fmt.Errorf("cannot scan %T into Money", src)
+	}
+	return nil
+}
+
+// parse membaca teks desimal seperti "150000.50" tanpa melewati float
+// agar nominal besar tetap presisi. Pecahan dibulatkan setengah menjauhi nol.
+func (m *Money) parse(value string) error {
+	text := strings.TrimSpace(value)
+	negative := strings.HasPrefix(text, "-")
+	text = strings.TrimPrefix(text, "-")
+
+	whole, fraction, _ := strings.Cut(text, ".")
+	if (whole == "" && fraction == "") || !isDigits(whole) || !isDigits(fraction) {
+		return fmt.Errorf("invalid money value %q", value)
+	}
+	if whole == "" {
+		whole = "0"
+	}
+	amount, err := strconv.ParseInt(whole, 10, 64)
+	if err != nil {
+		return fmt.Errorf("invalid money value %q", value)
+	}
+	if fraction != "" && fraction[0] >= '5' {
+		amount++
+	}
+
+	if negative {
+		amount = -amount
+	}
+	*m = Money(amount)
+	return nil
+}
+
+// isDigits true jika value hanya berisi angka 0-9 (string kosong dianggap valid)
+func isDigits(value string) bool {
+	for _, r := range value {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// UnmarshalJSON menerima angka maupun string desimal dari request
+func (m *Money) UnmarshalJSON(data []byte) error {
+	text := strings.Trim(string(data), `"`)
+	if text == "null" || text == "" {
+		*m = 0
+		return nil
+	}
+	return m.parse(text)
+}
+
+// MarshalJSON menulis Money sebagai angka bulat
+func (m Money) MarshalJSON() ([]byte, error) {
+	return json.Marshal(int64(m))
+}
+
+// String memformat nominal sebagai rupiah, contoh "Rp 1.500.000"
+func (m Money) String() string {
+	digits := strconv.FormatInt(int64(m), 10)
+	sign := ""
+	if m < 0 {
+		sign = "-"
+		digits = digits[1:]
+	}
+
+	var grouped strings.Builder
+	for i, digit := range digits {
+		if i > 0 && (len(digits)-i)%3 == 0 {
+			grouped.WriteByte('.')
+		}
+		grouped.WriteRune(digit)
+	}
+	return sign + "Rp " + grouped.String()
+}
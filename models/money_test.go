@@ -0,0 +1,105 @@
+package models
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestMoneyParse(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    Money
+		wantErr bool
+	}{
+		{input: "150000", want: 150000},
+		{input: "150000.5", want: 150001},
+		{input: "150000.49", want: 150000},
+		{input: "-150000.5", want: -150001},
+		{input: "-150000.49", want: -150000},
+		{input: "0.49", want: 0},
+		{input: "0.5", want: 1},
+		{input: ".5", want: 1},
+		{input: " 1000 ", want: 1000},
+		{input: "abc", wantErr: true},
+		{input: "12.5abc", wantErr: true},
+		{input: "1.2.3", wantErr: true},
+		{input: "--5", wantErr: true},
+		{input: "+5", wantErr: true},
+		{input: "1e3", wantErr: true},
+		{input: "-", wantErr: true},
+		{input: ".", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		var got Money
+		err := got.parse(tt.input)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("parse(%q) = %d, want error", tt.input, got)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("parse(%q) returned error: %v", tt.input, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("parse(%q) = %d, want %d", tt.input, got, tt.want)
+		}
+	}
+}
+
+func TestMoneyPercent(t *testing.T) {
+	tests := []struct {
+		amount  Money
+		percent float64
+		want    Money
+	}{
+		{amount: 150000, percent: 10, want: 15000},
+		{amount: 5, percent: 10, want: 1},   // 0.5 dibulatkan menjauhi nol
+		{amount: -5, percent: 10, want: -1}, // -0.5 dibulatkan menjauhi nol
+		{amount: 4, percent: 10, want: 0},   // 0.4 dibulatkan ke bawah
+		{amount: 333333, percent: 11, want: 36667},
+	}
+
+	for _, tt := range tests {
+		if got := tt.amount.Percent(tt.percent); got != tt.want {
+			t.Errorf("Money(%d).Percent(%g) = %d, want %d", tt.amount, tt.percent, got, tt.want)
+		}
+	}
+}
+
+func TestMoneyMultiply(t *testing.T) {
+	tests := []struct {
+		amount Money
+		factor float64
+		want   Money
+	}{
+		{amount: 300000, factor: 1.5, want: 450000},
+		{amount: 3, factor: 0.5, want: 2},   // 1.5 dibulatkan menjauhi nol
+		{amount: -3, factor: 0.5, want: -2}, // -1.5 dibulatkan menjauhi nol
+		{amount: 1, factor: 0.49, want: 0},
+	}
+
+	for _, tt := range tests {
+		if got := tt.amount.Multiply(tt.factor); got != tt.want {
+			t.Errorf("Money(%d).Multiply(%g) = %d, want %d", tt.amount, tt.factor, got, tt.want)
+		}
+	}
+}
+
+func TestMoneyUnmarshalJSON(t *testing.T) {
+	var payload struct {
+		Amount Money `json:"amount"`
+	}
+	if err := json.Unmarshal([]byte(`{"amount": "150000.5"}`), &payload); err != nil {
+		t.Fatal(err)
+	}
+	if payload.Amount != 150001 {
+		t.Errorf("amount = %d, want 150001", payload.Amount)
+	}
+
+	if err := json.Unmarshal([]byte(`{"amount": "abc"}`), &payload); err == nil {
+		t.Error("expected error for malformed amount")
+	}
+}
@@ -0,0 +1,28 @@
+package models
+
+import "time"
+
+// Jenis pembayaran
+const (
+	PaymentTypePayment = "payment" // Pelunasan atau cicilan
+	PaymentTypeDeposit = "deposit" // Uang muka saat booking
+	PaymentTypeRefund  = "refund"  // Pengembalian dana ke pelanggan
+)
+
+// Metode pembayaran
+const (
+	PaymentMethodCash         = "cash"
+	PaymentMethodBankTransfer = "bank_transfer"
+	PaymentMethodQRIS         = "qris"
+)
+
+type Payment struct {
+	ID        int       `json:"id" db:"id"`
+	BookingID int       `json:"booking_id" db:"booking_id"`
+	Type      string    `json:"type" db:"type"`           // payment, deposit atau refund
+	Method    string    `json:"method" db:"method"`       // cash, bank_transfer atau qris
+	Amount    Money     `json:"amount" db:"amount"`       // Selalu positif, arah ditentukan Type
+	Reference string    `json:"reference" db:"reference"` // Nomor referensi transfer/QRIS
+	Note      string    `json:"note" db:"note"`
+	PaidAt    time.Time `json:"paid_at" db:"paid_at"`
+}
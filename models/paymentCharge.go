@@ -0,0 +1,25 @@
+package models
+
+import "time"
+
+// Status tagihan online di payment gateway
+const (
+	ChargeStatusPending  = "pending"
+	ChargeStatusPaid     = "paid"
+	ChargeStatusFailed   = "failed"
+	ChargeStatusRefunded = "refunded"
+)
+
+type PaymentCharge struct {
+	ID             int       `json:"id" db:"id"`
+	BookingID      int       `json:"booking_id" db:"booking_id"`
+	Provider       string    `json:"provider" db:"provider"`       // Nama payment gateway
+	ExternalID     string    `json:"external_id" db:"external_id"` // ID tagihan di payment gateway
+	Amount         Money     `json:"amount" db:"amount"`
+	RefundedAmount Money     `json:"refunded_amount" db:"refunded_amount"` // Total dana yang sudah dikembalikan
+	Status         string    `json:"status" db:"status"`
+	CheckoutURL    string    `json:"checkout_url" db:"checkout_url"`
+	PaymentID      *int      `json:"payment_id" db:"payment_id"` // Baris payments yang tercatat saat lunas
+	CreatedAt      time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt      time.Time `json:"updated_at" db:"updated_at"`
+}
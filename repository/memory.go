@@ -0,0 +1,183 @@
+package repository
+
+import (
+	"fmt"
+	"maps"
+	"rental-mobil/models"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+// memoryStore menyimpan seluruh agregat di memori. Semua repository in-memory
+// berbagi satu store dan satu mutex sehingga aturan lintas agregat (stok
+// mobil, jadwal supir, booking yang menghalangi penghapusan) tetap berlaku.
+type memoryStore struct {
+	mu           sync.Mutex
+	lastID       map[string]int
+	cars         map[int]models.Car
+	customers    map[int]models.Customer
+	bookings     map[int]models.Booking
+	drivers      map[int]models.Driver
+	memberships  map[int]models.Membership
+	bookingTypes map[int]models.BookingType
+	history      []models.MembershipHistory
+	incentives   []models.DriverIncentive
+}
+
+// NewMemory membuat repository in-memory yang kosong, dipakai untuk menguji
+// controller tanpa database
+func NewMemory() *Repositories {
+	store := &memoryStore{
+		lastID:       map[string]int{},
+		cars:         map[int]models.Car{},
+		customers:    map[int]models.Customer{},
+		bookings:     map[int]models.Booking{},
+		drivers:      map[int]models.Driver{},
+		memberships:  map[int]models.Membership{},
+		bookingTypes: map[int]models.BookingType{},
+	}
+	return &Repositories{
+		Cars:         &memoryCarRepository{store: store},
+		Customers:    &memoryCustomerRepository{store: store},
+		Bookings:     &memoryBookingRepository{store: store},
+		Drivers:      &memoryDriverRepository{store: store},
+		Memberships:  &memoryMembershipRepository{store: store},
+		BookingTypes: &memoryBookingTypeRepository{store: store},
+	}
+}
+
+// nextID memberi id berurutan per tabel seperti kolom SERIAL
+func (s *memoryStore) nextID(table string) int {
+	s.lastID[table]++
+	return s.lastID[table]
+}
+
+// sortedValues mengembalikan isi map berurutan berdasarkan id
+func sortedValues[T any](items map[int]T) []T {
+	values := []T{}
+	for _, id := range slices.Sorted(maps.Keys(items)) {
+		values = append(values, items[id])
+	}
+	return values
+}
+
+// paginate memotong hasil list sesuai limit dan offset
+func paginate[T any](items []T, limit, offset int) []T {
+	if offset >= len(items) {
+		return []T{}
+	}
+	end := offset + limit
+	if end > len(items) {
+		end = len(items)
+	}
+	return items[offset:end]
+}
+
+// rentDay mengambil bagian tanggal YYYY-MM-DD dari tanggal sewa
+func rentDay(value string) string {
+	if len(value) > 10 {
+		return value[:10]
+	}
+	return value
+}
+
+// rentDays menghitung durasi sewa dalam hari
+func rentDays(booking models.Booking) int {
+	start, err := time.Parse("2006-01-02", rentDay(booking.StartRent))
+	if err != nil {
+		return 0
+	}
+	end, err := time.Parse("2006-01-02", rentDay(booking.EndRent))
+	if err != nil {
+		return 0
+	}
+	return int(end.Sub(start).Hours() / 24)
+}
+
+// countOverlapping menghitung booking aktif yang cocok dengan match dan
+// beririsan dengan [start, end). Pemanggil harus memegang mutex.
+func (s *memoryStore) countOverlapping(match func(models.Booking) bool, start, end string, excludeID int) int {
+	count := 0
+	for _, booking := range s.bookings {
+		if booking.ID == excludeID || booking.DeletedAt != nil || !models.IsActiveBookingStatus(booking.Status) || !match(booking) {
+			continue
+		}
+		if rentDay(booking.StartRent) < rentDay(end) && rentDay(booking.EndRent) > rentDay(start) {
+			count++
+		}
+	}
+	return count
+}
+
+// reserve memastikan stok mobil dan jadwal supir masih tersedia untuk booking
+func (s *memoryStore) reserve(booking *models.Booking, excludeID int) error {
+	car, ok := s.cars[booking.CarID]
+	if !ok || car.DeletedAt != nil {
+		return fmt.Errorf("car %d not found", booking.CarID)
+	}
+
+	booked := s.countOverlapping(func(b models.Booking) bool { return b.CarID == booking.CarID }, booking.StartRent, booking.EndRent, excludeID)
+	if booked >= car.Stock {
+		return ErrCarFullyBooked
+	}
+
+	if booking.DriverID == nil {
+		return nil
+	}
+	assigned := s.countOverlapping(func(b models.Booking) bool {
+		return b.DriverID != nil && *b.DriverID == *booking.DriverID
+	}, booking.StartRent, booking.EndRent, excludeID)
+	if assigned > 0 {
+		return ErrDriverUnavailable
+	}
+
+	return nil
+}
+
+// cancelBlocking adalah padanan cancelBlockingBookings untuk store in-memory
+func (s *memoryStore) cancelBlocking(match func(models.Booking) bool, force bool, reason string) ([]int, error) {
+	today := time.Now().Format("2006-01-02")
+	blocking := []models.BlockingBooking{}
+	for _, booking := range s.bookings {
+		if booking.DeletedAt != nil || !match(booking) {
+			continue
+		}
+		if booking.Status == models.BookingStatusPickedUp ||
+			(booking.Status == models.BookingStatusReserved && rentDay(booking.EndRent) >= today) {
+			blocking = append(blocking, models.BlockingBooking{
+				ID:         booking.ID,
+				CustomerID: booking.CustomerID,
+				CarID:      booking.CarID,
+				StartRent:  booking.StartRent,
+				EndRent:    booking.EndRent,
+				Status:     booking.Status,
+			})
+		}
+	}
+	slices.SortFunc(blocking, func(a, b models.BlockingBooking) int {
+		if order := strings.Compare(a.StartRent, b.StartRent); order != 0 {
+			return order
+		}
+		return a.ID - b.ID
+	})
+
+	if err := checkBlockingBookings(blocking, force); err != nil {
+		return nil, err
+	}
+
+	cancelled := []int{}
+	now := time.Now()
+	for _, blocked := range blocking {
+		booking := s.bookings[blocked.ID]
+		booking.Status = models.BookingStatusCancelled
+		booking.CancelledAt = &now
+		booking.CancellationFee = 0
+		booking.CancelReason = &reason
+		s.bookings[booking.ID] = booking
+		cancelled = append(cancelled, booking.ID)
+	}
+
+	return cancelled, nil
+}
@@ -0,0 +1,128 @@
+package repository
+
+import (
+	"rental-mobil/models"
+	"rental-mobil/services"
+	"time"
+)
+
+type memoryBookingRepository struct {
+	store *memoryStore
+}
+
+// List tidak mengenal pembayaran, sehingga sisa tagihan selalu sama dengan
+// total tagihan booking
+func (r *memoryBookingRepository) List(limit, offset int, includeDeleted bool) ([]models.Booking, int, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	bookings := []models.Booking{}
+	for _, booking := range sortedValues(r.store.bookings) {
+		if includeDeleted || booking.DeletedAt == nil {
+			booking.BalanceDue = memoryBalance(&booking).BalanceDue
+			bookings = append(bookings, booking)
+		}
+	}
+	return paginate(bookings, limit, offset), len(bookings), nil
+}
+
+func (r *memoryBookingRepository) CountOverlapping(carID int, start, end string) (int, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	return r.store.countOverlapping(func(b models.Booking) bool { return b.CarID == carID }, start, end, 0), nil
+}
+
+func (r *memoryBookingRepository) CountDriverOverlapping(driverID int, start, end string) (int, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	return r.store.countOverlapping(func(b models.Booking) bool {
+		return b.DriverID != nil && *b.DriverID == driverID
+	}, start, end, 0), nil
+}
+
+func (r *memoryBookingRepository) Create(booking *models.Booking, price *services.PriceBreakdown) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if err := r.store.reserve(booking, 0); err != nil {
+		return err
+	}
+
+	applyBookingPrice(booking, price)
+	now := time.Now()
+	booking.ID = r.store.nextID("bookings")
+	booking.Status = models.BookingStatusReserved
+	booking.ReservedAt = &now
+	r.store.bookings[booking.ID] = *booking
+	return nil
+}
+
+func (r *memoryBookingRepository) Update(booking *models.Booking, price *services.PriceBreakdown) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	existing, ok := r.store.bookings[booking.ID]
+	if !ok || existing.DeletedAt != nil {
+		return ErrNotFound
+	}
+	if !models.IsActiveBookingStatus(existing.Status) {
+		return ErrBookingNotActive
+	}
+
+	if err := r.store.reserve(booking, booking.ID); err != nil {
+		return err
+	}
+
+	applyBookingPrice(booking, price)
+	existing.CustomerID = booking.CustomerID
+	existing.CarID = booking.CarID
+	existing.StartRent = booking.StartRent
+	existing.EndRent = booking.EndRent
+	existing.TotalCost = booking.TotalCost
+	existing.Discount = booking.Discount
+	existing.BookingTypeID = booking.BookingTypeID
+	existing.DriverID = booking.DriverID
+	existing.TotalDriverCost = booking.TotalDriverCost
+	existing.DepositAmount = booking.DepositAmount
+	r.store.bookings[booking.ID] = existing
+	return nil
+}
+
+func (r *memoryBookingRepository) Delete(id int) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	booking, ok := r.store.bookings[id]
+	if !ok || booking.DeletedAt != nil {
+		return ErrNotFound
+	}
+	if models.IsActiveBookingStatus(booking.Status) {
+		return ErrBookingActive
+	}
+	now := time.Now()
+	booking.DeletedAt = &now
+	r.store.bookings[id] = booking
+	return nil
+}
+
+func (r *memoryBookingRepository) Restore(id int) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	booking, ok := r.store.bookings[id]
+	if !ok || booking.DeletedAt == nil {
+		return ErrNotFound
+	}
+
+	if models.IsActiveBookingStatus(booking.Status) {
+		if err := r.store.reserve(&booking, id); err != nil {
+			return err
+		}
+	}
+
+	booking.DeletedAt = nil
+	r.store.bookings[id] = booking
+	return nil
+}
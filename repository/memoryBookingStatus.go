@@ -0,0 +1,136 @@
+package repository
+
+import (
+	"fmt"
+	"rental-mobil/models"
+	"rental-mobil/services"
+	"time"
+)
+
+// Pickup menolak mobil dengan uang jaminan karena store in-memory tidak
+// mencatat uang jaminan yang ditahan
+func (r *memoryBookingRepository) Pickup(id int) (*models.Booking, error) {
+	return r.transition(id, models.BookingStatusPickedUp, func(booking *models.Booking) error {
+		if booking.DepositAmount > 0 {
+			return &DepositRequiredError{Required: booking.DepositAmount}
+		}
+		return nil
+	})
+}
+
+// Return menganggap tagihan belum dibayar karena store in-memory tidak
+// mencatat pembayaran
+func (r *memoryBookingRepository) Return(id int, returnDate string, overrideBalance bool) (*models.Booking, error) {
+	return r.transition(id, models.BookingStatusReturned, func(booking *models.Booking) error {
+		dailyRate := r.store.cars[booking.CarID].DailyRent
+		if booking.DriverID != nil {
+			dailyRate += r.store.drivers[*booking.DriverID].DailyCost
+		}
+
+		_, lateFee, err := services.CalculateLateFee(booking, returnDate, dailyRate)
+		if err != nil {
+			return err
+		}
+		booking.LateFee = lateFee
+		booking.ReturnDate = &returnDate
+
+		if balance := memoryBalance(booking); balance.BalanceDue > 0 && !overrideBalance {
+			return &OutstandingBalanceError{BalanceDue: balance.BalanceDue}
+		}
+
+		if booking.DriverID != nil {
+			incentive, err := services.CalculateDriverIncentive(booking)
+			if err != nil {
+				return err
+			}
+			r.store.incentives = append(r.store.incentives, models.DriverIncentive{
+				ID:        r.store.nextID("driver_incentive"),
+				BookingID: booking.ID,
+				Incentive: incentive,
+			})
+		}
+		return nil
+	})
+}
+
+// Cancel mengabaikan refundMethod karena belum ada pembayaran yang bisa
+// direfund. Biaya pembatalan tanpa pajak karena store in-memory tidak
+// menyimpan invoice.
+func (r *memoryBookingRepository) Cancel(id int, reason, refundMethod string) (*models.Booking, error) {
+	return r.transition(id, models.BookingStatusCancelled, func(booking *models.Booking) error {
+		bookingType, ok := r.store.bookingTypes[booking.BookingTypeID]
+		if !ok {
+			return fmt.Errorf("booking type %d not found", booking.BookingTypeID)
+		}
+
+		fee, err := services.CalculateCancellationFee(booking, bookingType.CancellationPolicy, time.Now())
+		if err != nil {
+			return err
+		}
+		booking.CancellationFee = fee
+		booking.CancelReason = &reason
+		return nil
+	})
+}
+
+func (r *memoryBookingRepository) NoShow(id int) (*models.Booking, error) {
+	return r.transition(id, models.BookingStatusNoShow, nil)
+}
+
+func (r *memoryBookingRepository) Balance(id int) (*services.BookingBalance, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	booking, ok := r.store.bookings[id]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	return memoryBalance(&booking), nil
+}
+
+// transition adalah padanan transition Postgres. Booking hanya disimpan jika
+// afterFn berhasil sehingga kegagalan tidak meninggalkan status setengah jalan.
+func (r *memoryBookingRepository) transition(id int, to string, afterFn func(booking *models.Booking) error) (*models.Booking, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	booking, ok := r.store.bookings[id]
+	if !ok || booking.DeletedAt != nil {
+		return nil, ErrNotFound
+	}
+	if !models.CanTransitionBooking(booking.Status, to) {
+		return nil, &TransitionError{From: booking.Status, To: to}
+	}
+
+	now := time.Now()
+	booking.Status = to
+	switch to {
+	case models.BookingStatusPickedUp:
+		booking.PickedUpAt = &now
+	case models.BookingStatusReturned:
+		booking.ReturnedAt = &now
+	case models.BookingStatusCancelled:
+		booking.CancelledAt = &now
+	case models.BookingStatusNoShow:
+		booking.NoShowAt = &now
+	}
+
+	if afterFn != nil {
+		if err := afterFn(&booking); err != nil {
+			return nil, err
+		}
+	}
+
+	r.store.bookings[id] = booking
+	return &booking, nil
+}
+
+// memoryBalance menghitung tagihan booking tanpa pajak karena store
+// in-memory tidak menyimpan invoice
+func memoryBalance(booking *models.Booking) *services.BookingBalance {
+	total := booking.TotalCost + booking.TotalDriverCost + booking.LateFee
+	if booking.Status == models.BookingStatusCancelled {
+		total = booking.CancellationFee
+	}
+	return &services.BookingBalance{Total: total, BalanceDue: total}
+}
@@ -0,0 +1,65 @@
+package repository
+
+import (
+	"rental-mobil/models"
+)
+
+type memoryBookingTypeRepository struct {
+	store *memoryStore
+}
+
+func (r *memoryBookingTypeRepository) List(limit, offset int) ([]models.BookingType, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	return paginate(sortedValues(r.store.bookingTypes), limit, offset), nil
+}
+
+func (r *memoryBookingTypeRepository) Get(id int) (*models.BookingType, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	bookingType, ok := r.store.bookingTypes[id]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	return &bookingType, nil
+}
+
+func (r *memoryBookingTypeRepository) Create(bookingType *models.BookingType) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	bookingType.ID = r.store.nextID("booking_type")
+	r.store.bookingTypes[bookingType.ID] = *bookingType
+	return nil
+}
+
+func (r *memoryBookingTypeRepository) Update(bookingType *models.BookingType) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if _, ok := r.store.bookingTypes[bookingType.ID]; !ok {
+		return ErrNotFound
+	}
+	r.store.bookingTypes[bookingType.ID] = *bookingType
+	return nil
+}
+
+func (r *memoryBookingTypeRepository) Delete(id int) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	// Tolak jika jenis booking masih dipakai booking
+	for _, booking := range r.store.bookings {
+		if booking.BookingTypeID == id {
+			return ErrInUse
+		}
+	}
+
+	if _, ok := r.store.bookingTypes[id]; !ok {
+		return ErrNotFound
+	}
+	delete(r.store.bookingTypes, id)
+	return nil
+}
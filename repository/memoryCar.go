@@ -0,0 +1,105 @@
+package repository
+
+import (
+	"rental-mobil/models"
+	"time"
+)
+
+type memoryCarRepository struct {
+	store *memoryStore
+}
+
+func (r *memoryCarRepository) List(limit, offset int, includeDeleted bool) ([]models.Car, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	cars := []models.Car{}
+	for _, car := range sortedValues(r.store.cars) {
+		if includeDeleted || car.DeletedAt == nil {
+			cars = append(cars, car)
+		}
+	}
+	return paginate(cars, limit, offset), nil
+}
+
+func (r *memoryCarRepository) ListActive() ([]models.Car, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	cars := []models.Car{}
+	for _, car := range sortedValues(r.store.cars) {
+		if car.DeletedAt == nil {
+			cars = append(cars, car)
+		}
+	}
+	return cars, nil
+}
+
+func (r *memoryCarRepository) Get(id int) (*models.Car, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	car, ok := r.store.cars[id]
+	if !ok || car.DeletedAt != nil {
+		return nil, ErrNotFound
+	}
+	return &car, nil
+}
+
+func (r *memoryCarRepository) Create(car *models.Car) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	car.ID = r.store.nextID("cars")
+	r.store.cars[car.ID] = *car
+	return nil
+}
+
+func (r *memoryCarRepository) Update(car *models.Car) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	existing, ok := r.store.cars[car.ID]
+	if !ok || existing.DeletedAt != nil {
+		return ErrNotFound
+	}
+	existing.Name = car.Name
+	existing.Stock = car.Stock
+	existing.DailyRent = car.DailyRent
+	existing.DepositAmount = car.DepositAmount
+	r.store.cars[car.ID] = existing
+	return nil
+}
+
+func (r *memoryCarRepository) Delete(id int, force bool) ([]int, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	car, ok := r.store.cars[id]
+	if !ok || car.DeletedAt != nil {
+		return nil, ErrNotFound
+	}
+
+	cancelled, err := r.store.cancelBlocking(func(b models.Booking) bool { return b.CarID == id }, force, "Mobil dihapus")
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	car.DeletedAt = &now
+	r.store.cars[id] = car
+	return cancelled, nil
+}
+
+func (r *memoryCarRepository) Restore(id int) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	car, ok := r.store.cars[id]
+	if !ok || car.DeletedAt == nil {
+		return ErrNotFound
+	}
+	car.DeletedAt = nil
+	r.store.cars[id] = car
+	return nil
+}
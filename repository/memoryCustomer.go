@@ -0,0 +1,213 @@
+package repository
+
+import (
+	"rental-mobil/models"
+	"rental-mobil/services"
+	"slices"
+	"strings"
+	"time"
+)
+
+type memoryCustomerRepository struct {
+	store *memoryStore
+}
+
+func (r *memoryCustomerRepository) List(limit, offset int, includeDeleted bool) ([]models.Customer, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	customers := []models.Customer{}
+	for _, customer := range sortedValues(r.store.customers) {
+		if includeDeleted || customer.DeletedAt == nil {
+			customers = append(customers, customer)
+		}
+	}
+	return paginate(customers, limit, offset), nil
+}
+
+func (r *memoryCustomerRepository) Get(id int) (*models.Customer, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	customer, ok := r.store.customers[id]
+	if !ok || customer.DeletedAt != nil {
+		return nil, ErrNotFound
+	}
+	return &customer, nil
+}
+
+func (r *memoryCustomerRepository) Exists(id int) (bool, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	customer, ok := r.store.customers[id]
+	return ok && customer.DeletedAt == nil, nil
+}
+
+func (r *memoryCustomerRepository) NIKExists(nik string, excludeID int) (bool, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	for _, customer := range r.store.customers {
+		if customer.NIK == nik && customer.ID != excludeID {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (r *memoryCustomerRepository) Create(customer *models.Customer) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	customer.ID = r.store.nextID("customers")
+	r.store.customers[customer.ID] = *customer
+
+	// Catat membership awal ke riwayat
+	if customer.MembershipID != nil {
+		r.store.insertMembershipHistory(customer.ID, nil, customer.MembershipID, time.Now().Format("2006-01-02"))
+	}
+	return nil
+}
+
+func (r *memoryCustomerRepository) Update(customer *models.Customer) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	existing, ok := r.store.customers[customer.ID]
+	if !ok || existing.DeletedAt != nil {
+		return ErrNotFound
+	}
+	// Update hanya field yang dikirim; field kosong tidak menimpa data lama
+	if customer.Name != "" {
+		existing.Name = customer.Name
+	}
+	if customer.NIK != "" {
+		existing.NIK = customer.NIK
+	}
+	if customer.Phone != "" {
+		existing.Phone = customer.Phone
+	}
+	r.store.customers[customer.ID] = existing
+
+	// Perubahan membership ikut dicatat ke riwayat
+	if customer.MembershipID != nil {
+		return r.store.changeCustomerMembership(customer.ID, customer.MembershipID, time.Now().Format("2006-01-02"))
+	}
+	return nil
+}
+
+func (r *memoryCustomerRepository) ChangeMembership(id int, membershipID *int, effectiveDate string) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	return r.store.changeCustomerMembership(id, membershipID, effectiveDate)
+}
+
+func (r *memoryCustomerRepository) MembershipHistory(id int) ([]models.MembershipHistory, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	history := []models.MembershipHistory{}
+	for _, entry := range r.store.history {
+		if entry.CustomerID == id {
+			history = append(history, entry)
+		}
+	}
+	// Riwayat disimpan berurutan id, cukup urutkan ulang per tanggal berlaku
+	slices.SortStableFunc(history, func(a, b models.MembershipHistory) int {
+		return strings.Compare(a.EffectiveDate, b.EffectiveDate)
+	})
+	return history, nil
+}
+
+func (r *memoryCustomerRepository) RentalStats(since string) ([]services.CustomerRentalStats, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	stats := []services.CustomerRentalStats{}
+	for _, customer := range sortedValues(r.store.customers) {
+		if customer.DeletedAt != nil {
+			continue
+		}
+		s := services.CustomerRentalStats{CustomerID: customer.ID, MembershipID: customer.MembershipID}
+		for _, booking := range r.store.bookings {
+			if booking.CustomerID != customer.ID || booking.DeletedAt != nil ||
+				booking.Status != models.BookingStatusReturned || rentDay(booking.EndRent) < since {
+				continue
+			}
+			s.Rentals++
+			s.Spend += booking.TotalCost + booking.TotalDriverCost + booking.LateFee
+			s.Days += rentDays(booking)
+		}
+		stats = append(stats, s)
+	}
+	return stats, nil
+}
+
+func (r *memoryCustomerRepository) Delete(id int, force bool) ([]int, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	customer, ok := r.store.customers[id]
+	if !ok || customer.DeletedAt != nil {
+		return nil, ErrNotFound
+	}
+
+	cancelled, err := r.store.cancelBlocking(func(b models.Booking) bool { return b.CustomerID == id }, force, "Pelanggan dihapus")
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	customer.DeletedAt = &now
+	r.store.customers[id] = customer
+	return cancelled, nil
+}
+
+func (r *memoryCustomerRepository) Restore(id int) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	customer, ok := r.store.customers[id]
+	if !ok || customer.DeletedAt == nil {
+		return ErrNotFound
+	}
+	customer.DeletedAt = nil
+	r.store.customers[id] = customer
+	return nil
+}
+
+// changeCustomerMembership adalah padanan in-memory dari
+// changeCustomerMembership milik Postgres. Pemanggil harus memegang mutex.
+func (s *memoryStore) changeCustomerMembership(customerID int, membershipID *int, effectiveDate string) error {
+	customer, ok := s.customers[customerID]
+	if !ok || customer.DeletedAt != nil {
+		return ErrNotFound
+	}
+
+	current := customer.MembershipID
+	if current == nil && membershipID == nil {
+		return nil
+	}
+	if current != nil && membershipID != nil && *current == *membershipID {
+		return nil
+	}
+
+	customer.MembershipID = membershipID
+	s.customers[customerID] = customer
+	s.insertMembershipHistory(customerID, current, membershipID, effectiveDate)
+	return nil
+}
+
+// insertMembershipHistory menyimpan satu baris riwayat membership
+func (s *memoryStore) insertMembershipHistory(customerID int, oldID, newID *int, effectiveDate string) {
+	s.history = append(s.history, models.MembershipHistory{
+		ID:              s.nextID("membership_history"),
+		CustomerID:      customerID,
+		OldMembershipID: oldID,
+		NewMembershipID: newID,
+		EffectiveDate:   effectiveDate,
+		CreatedAt:       time.Now(),
+	})
+}
@@ -0,0 +1,120 @@
+package repository
+
+import (
+	"rental-mobil/models"
+	"slices"
+	"strings"
+)
+
+type memoryDriverRepository struct {
+	store *memoryStore
+}
+
+func (r *memoryDriverRepository) List(limit, offset int) ([]models.Driver, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	return paginate(sortedValues(r.store.drivers), limit, offset), nil
+}
+
+func (r *memoryDriverRepository) Get(id int) (*models.Driver, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	driver, ok := r.store.drivers[id]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	return &driver, nil
+}
+
+func (r *memoryDriverRepository) NIKExists(nik string, excludeID int) (bool, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	for _, driver := range r.store.drivers {
+		if driver.NIK == nik && driver.ID != excludeID {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (r *memoryDriverRepository) Create(driver *models.Driver) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	driver.ID = r.store.nextID("drivers")
+	r.store.drivers[driver.ID] = *driver
+	return nil
+}
+
+func (r *memoryDriverRepository) Update(driver *models.Driver) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if _, ok := r.store.drivers[driver.ID]; !ok {
+		return ErrNotFound
+	}
+	r.store.drivers[driver.ID] = *driver
+	return nil
+}
+
+func (r *memoryDriverRepository) Delete(id int) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	// Tolak jika supir masih tercatat di booking
+	for _, booking := range r.store.bookings {
+		if booking.DriverID != nil && *booking.DriverID == id {
+			return ErrInUse
+		}
+	}
+
+	if _, ok := r.store.drivers[id]; !ok {
+		return ErrNotFound
+	}
+	delete(r.store.drivers, id)
+	return nil
+}
+
+func (r *memoryDriverRepository) ListAvailable(start, end string) ([]models.Driver, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	drivers := []models.Driver{}
+	for _, driver := range sortedValues(r.store.drivers) {
+		assigned := r.store.countOverlapping(func(b models.Booking) bool {
+			return b.DriverID != nil && *b.DriverID == driver.ID
+		}, start, end, 0)
+		if assigned == 0 {
+			drivers = append(drivers, driver)
+		}
+	}
+	return drivers, nil
+}
+
+// Incentives memfilter insentif berdasarkan end_rent booking seperti padanan
+// Postgres-nya
+func (r *memoryDriverRepository) Incentives(id int, from, to string) ([]models.DriverIncentive, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if _, ok := r.store.drivers[id]; !ok {
+		return nil, ErrNotFound
+	}
+
+	incentives := []models.DriverIncentive{}
+	for _, incentive := range r.store.incentives {
+		booking := r.store.bookings[incentive.BookingID]
+		endRent := rentDay(booking.EndRent)
+		if booking.DriverID == nil || *booking.DriverID != id || (from != "" && endRent < from) || (to != "" && endRent > to) {
+			continue
+		}
+		incentives = append(incentives, incentive)
+	}
+	slices.SortStableFunc(incentives, func(a, b models.DriverIncentive) int {
+		return strings.Compare(rentDay(r.store.bookings[a.BookingID].EndRent), rentDay(r.store.bookings[b.BookingID].EndRent))
+	})
+	return incentives, nil
+}
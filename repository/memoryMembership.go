@@ -0,0 +1,91 @@
+package repository
+
+import (
+	"rental-mobil/models"
+	"slices"
+)
+
+type memoryMembershipRepository struct {
+	store *memoryStore
+}
+
+func (r *memoryMembershipRepository) List(limit, offset int) ([]models.Membership, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	return paginate(sortedValues(r.store.memberships), limit, offset), nil
+}
+
+func (r *memoryMembershipRepository) ListTiers() ([]models.Membership, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	tiers := sortedValues(r.store.memberships)
+	slices.SortStableFunc(tiers, func(a, b models.Membership) int {
+		switch {
+		case a.Discount > b.Discount:
+			return -1
+		case a.Discount < b.Discount:
+			return 1
+		}
+		return 0
+	})
+	return tiers, nil
+}
+
+func (r *memoryMembershipRepository) Get(id int) (*models.Membership, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	membership, ok := r.store.memberships[id]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	return &membership, nil
+}
+
+func (r *memoryMembershipRepository) Exists(id int) (bool, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	_, ok := r.store.memberships[id]
+	return ok, nil
+}
+
+func (r *memoryMembershipRepository) Create(membership *models.Membership) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	membership.ID = r.store.nextID("memberships")
+	r.store.memberships[membership.ID] = *membership
+	return nil
+}
+
+func (r *memoryMembershipRepository) Update(membership *models.Membership) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if _, ok := r.store.memberships[membership.ID]; !ok {
+		return ErrNotFound
+	}
+	r.store.memberships[membership.ID] = *membership
+	return nil
+}
+
+func (r *memoryMembershipRepository) Delete(id int) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	// Tolak jika masih ada pelanggan dengan membership ini
+	for _, customer := range r.store.customers {
+		if customer.MembershipID != nil && *customer.MembershipID == id {
+			return ErrInUse
+		}
+	}
+
+	if _, ok := r.store.memberships[id]; !ok {
+		return ErrNotFound
+	}
+	delete(r.store.memberships, id)
+	return nil
+}
@@ -0,0 +1,166 @@
+package repository
+
+import (
+	"database/sql"
+	"rental-mobil/models"
+	"rental-mobil/services"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// NewPostgres membuat repository yang menyimpan data di PostgreSQL
+func NewPostgres(db *sqlx.DB) *Repositories {
+	return &Repositories{
+		Cars:         &postgresCarRepository{db: db},
+		Customers:    &postgresCustomerRepository{db: db},
+		Bookings:     &postgresBookingRepository{db: db},
+		Drivers:      &postgresDriverRepository{db: db},
+		Memberships:  &postgresMembershipRepository{db: db},
+		BookingTypes: &postgresBookingTypeRepository{db: db},
+	}
+}
+
+// notFound mengubah sql.ErrNoRows menjadi ErrNotFound
+func notFound(err error) error {
+	if err == sql.ErrNoRows {
+		return ErrNotFound
+	}
+	return err
+}
+
+// requireAffected mengembalikan ErrNotFound jika query tidak mengubah baris apapun
+func requireAffected(result sql.Result, err error) error {
+	if err != nil {
+		return err
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// countOverlappingBookings menghitung booking aktif (reserved/picked_up) untuk car_id
+// tertentu yang rentang sewanya beririsan dengan [startRent, endRent).
+// excludeID dipakai saat update agar booking itu sendiri tidak ikut dihitung.
+func countOverlappingBookings(q sqlx.Queryer, carID int, startRent, endRent string, excludeID int) (int, error) {
+	var count int
+	query := `SELECT COUNT(*) FROM bookings
+        WHERE car_id = $1 AND status IN ('reserved', 'picked_up') AND deleted_at IS NULL
+        AND start_rent < $3 AND end_rent > $2
+        AND id <> $4`
+	err := sqlx.Get(q, &count, query, carID, startRent, endRent, excludeID)
+	return count, err
+}
+
+// countOverlappingDriverBookings menghitung booking aktif (reserved/picked_up) untuk
+// driver_id tertentu yang beririsan dengan [startRent, endRent).
+func countOverlappingDriverBookings(q sqlx.Queryer, driverID int, startRent, endRent string, excludeID int) (int, error) {
+	var count int
+	query := `SELECT COUNT(*) FROM bookings
+        WHERE driver_id = $1 AND status IN ('reserved', 'picked_up') AND deleted_at IS NULL
+        AND start_rent < $3 AND end_rent > $2
+        AND id <> $4`
+	err := sqlx.Get(q, &count, query, driverID, startRent, endRent, excludeID)
+	return count, err
+}
+
+// reserveBookingResources mengunci baris mobil (dan supir jika ada) lalu
+// memastikan stok mobil dan jadwal supir tidak bentrok dengan booking lain.
+// excludeID diisi saat update agar booking itu sendiri tidak ikut dihitung.
+func reserveBookingResources(tx *sqlx.Tx, booking *models.Booking, excludeID int) error {
+	var stock int
+	carQuery := `SELECT stock FROM cars WHERE id = $1 AND deleted_at IS NULL FOR UPDATE`
+	if err := tx.Get(&stock, carQuery, booking.CarID); err != nil {
+		return err
+	}
+
+	// Pastikan stok mobil masih tersedia pada rentang tanggal tersebut
+	booked, err := countOverlappingBookings(tx, booking.CarID, booking.StartRent, booking.EndRent, excludeID)
+	if err != nil {
+		return err
+	}
+	if booked >= stock {
+		return ErrCarFullyBooked
+	}
+
+	if booking.DriverID == nil {
+		return nil
+	}
+
+	// Supir tidak boleh ditugaskan ke dua booking yang beririsan
+	if _, err := tx.Exec(`SELECT id FROM driver WHERE id = $1 FOR UPDATE`, *booking.DriverID); err != nil {
+		return err
+	}
+	assigned, err := countOverlappingDriverBookings(tx, *booking.DriverID, booking.StartRent, booking.EndRent, excludeID)
+	if err != nil {
+		return err
+	}
+	if assigned > 0 {
+		return ErrDriverUnavailable
+	}
+
+	return nil
+}
+
+// cancelBlockingBookings mengunci booking milik mobil/pelanggan (column berisi
+// car_id atau customer_id) yang sedang berjalan atau belum dimulai. Tanpa
+// force dikembalikan BlockedError. Dengan force, booking yang belum diambil
+// dibatalkan tanpa biaya; booking yang mobilnya sedang disewa tetap harus
+// dikembalikan dulu.
+func cancelBlockingBookings(tx *sqlx.Tx, column string, id int, force bool, reason string) ([]int, error) {
+	blocking := []models.BlockingBooking{}
+	query := `SELECT id, customer_id, car_id, start_rent, end_rent, status FROM bookings
+        WHERE ` + column + ` = $1 AND deleted_at IS NULL
+        AND (status = 'picked_up' OR (status = 'reserved' AND end_rent >= CURRENT_DATE))
+        ORDER BY start_rent, id
+        FOR UPDATE`
+	if err := tx.Select(&blocking, query, id); err != nil {
+		return nil, err
+	}
+
+	if err := checkBlockingBookings(blocking, force); err != nil {
+		return nil, err
+	}
+
+	cancelled := []int{}
+	updateQuery := `UPDATE bookings SET status = $1, cancelled_at = NOW(), cancellation_fee = 0, cancellation_reason = $2
+        WHERE id = $3`
+	for _, booking := range blocking {
+		if _, err := tx.Exec(updateQuery, models.BookingStatusCancelled, reason, booking.ID); err != nil {
+			return nil, err
+		}
+		if _, err := services.SetInvoiceCancellationFee(tx, booking.ID, 0); err != nil {
+			return nil, err
+		}
+		cancelled = append(cancelled, booking.ID)
+	}
+
+	return cancelled, nil
+}
+
+// checkBlockingBookings menolak penghapusan jika masih ada booking yang
+// belum selesai dan force tidak diisi, atau ada mobil yang sedang disewa
+func checkBlockingBookings(blocking []models.BlockingBooking, force bool) error {
+	if len(blocking) == 0 {
+		return nil
+	}
+	if !force {
+		return &BlockedError{Bookings: blocking}
+	}
+
+	pickedUp := []models.BlockingBooking{}
+	for _, booking := range blocking {
+		if booking.Status == models.BookingStatusPickedUp {
+			pickedUp = append(pickedUp, booking)
+		}
+	}
+	if len(pickedUp) > 0 {
+		return &BlockedError{Bookings: pickedUp, PickedUp: true}
+	}
+
+	return nil
+}
@@ -0,0 +1,176 @@
+package repository
+
+import (
+	"rental-mobil/models"
+	"rental-mobil/services"
+
+	"github.com/jmoiron/sqlx"
+)
+
+type postgresBookingRepository struct {
+	db *sqlx.DB
+}
+
+func (r *postgresBookingRepository) List(limit, offset int, includeDeleted bool) ([]models.Booking, int, error) {
+	bookings := []models.Booking{}
+	query := `SELECT b.id, b.customer_id, b.car_id, b.start_rent, b.end_rent, b.total_cost, b.status, b.return_date, b.late_fee, b.deposit_amount, b.cancellation_fee, b.cancellation_reason, b.deleted_at,
+              b.reserved_at, b.picked_up_at, b.returned_at, b.cancelled_at, b.no_show_at,
+              COALESCE(p.paid, 0) AS amount_paid,
+              COALESCE(i.total, b.total_cost + b.total_driver_cost + b.late_fee) - COALESCE(p.paid, 0) AS balance_due
+              FROM bookings b
+              LEFT JOIN invoices i ON i.booking_id = b.id
+              LEFT JOIN (
+                  SELECT booking_id, SUM(CASE WHEN type = 'refund' THEN -amount ELSE amount END) AS paid
+                  FROM payments GROUP BY booking_id
+              ) p ON p.booking_id = b.id
+              WHERE $3 OR b.deleted_at IS NULL
+              ORDER BY b.id
+              LIMIT $1 OFFSET $2`
+	if err := r.db.Select(&bookings, query, limit, offset, includeDeleted); err != nil {
+		return nil, 0, err
+	}
+
+	var total int
+	countQuery := `SELECT COUNT(*) FROM bookings WHERE $1 OR deleted_at IS NULL`
+	if err := r.db.Get(&total, countQuery, includeDeleted); err != nil {
+		return nil, 0, err
+	}
+
+	return bookings, total, nil
+}
+
+func (r *postgresBookingRepository) CountOverlapping(carID int, start, end string) (int, error) {
+	return countOverlappingBookings(r.db, carID, start, end, 0)
+}
+
+func (r *postgresBookingRepository) CountDriverOverlapping(driverID int, start, end string) (int, error) {
+	return countOverlappingDriverBookings(r.db, driverID, start, end, 0)
+}
+
+func (r *postgresBookingRepository) Create(booking *models.Booking, price *services.PriceBreakdown) error {
+	// Transaksi agar pengecekan stok, insert dan invoice berjalan atomik
+	tx, err := r.db.Beginx()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	// Kunci mobil dan supir lalu pastikan keduanya masih tersedia
+	if err := reserveBookingResources(tx, booking, 0); err != nil {
+		return err
+	}
+
+	applyBookingPrice(booking, price)
+	insertQuery := `INSERT INTO bookings (customer_id, car_id, start_rent, end_rent, total_cost, status, reserved_at, discount, booking_type_id, driver_id, total_driver_cost, deposit_amount)
+        VALUES ($1, $2, $3, $4, $5, $6, NOW(), $7, $8, $9, $10, $11) RETURNING id`
+	err = tx.Get(&booking.ID, insertQuery, booking.CustomerID, booking.CarID, booking.StartRent, booking.EndRent, booking.TotalCost, models.BookingStatusReserved, booking.Discount, booking.BookingTypeID, booking.DriverID, booking.TotalDriverCost, booking.DepositAmount)
+	if err != nil {
+		return err
+	}
+
+	// Buat invoice bernomor dari rincian harga yang sama
+	if err := services.SaveBookingInvoice(tx, booking.ID, price); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func (r *postgresBookingRepository) Update(booking *models.Booking, price *services.PriceBreakdown) error {
+	tx, err := r.db.Beginx()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	var status string
+	checkQuery := `SELECT status FROM bookings WHERE id = $1 AND deleted_at IS NULL FOR UPDATE`
+	if err := tx.Get(&status, checkQuery, booking.ID); err != nil {
+		return notFound(err)
+	}
+
+	// Booking yang sudah selesai atau dibatalkan tidak bisa diubah lagi
+	if !models.IsActiveBookingStatus(status) {
+		return ErrBookingNotActive
+	}
+
+	if err := reserveBookingResources(tx, booking, booking.ID); err != nil {
+		return err
+	}
+
+	applyBookingPrice(booking, price)
+	updateQuery := `
+        UPDATE bookings
+        SET customer_id=$1, car_id=$2, start_rent=$3, end_rent=$4, total_cost=$5,
+            discount=$6, booking_type_id=$7, driver_id=$8, total_driver_cost=$9, deposit_amount=$10
+        WHERE id=$11`
+	_, err = tx.Exec(updateQuery, booking.CustomerID, booking.CarID, booking.StartRent, booking.EndRent, booking.TotalCost,
+		booking.Discount, booking.BookingTypeID, booking.DriverID, booking.TotalDriverCost, booking.DepositAmount, booking.ID)
+	if err != nil {
+		return err
+	}
+
+	// Sesuaikan baris invoice dengan harga yang baru
+	if err := services.SaveBookingInvoice(tx, booking.ID, price); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func (r *postgresBookingRepository) Delete(id int) error {
+	tx, err := r.db.Beginx()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	var status string
+	query := `SELECT status FROM bookings WHERE id = $1 AND deleted_at IS NULL FOR UPDATE`
+	if err := tx.Get(&status, query, id); err != nil {
+		return notFound(err)
+	}
+	if models.IsActiveBookingStatus(status) {
+		return ErrBookingActive
+	}
+
+	if _, err := tx.Exec(`UPDATE bookings SET deleted_at = NOW() WHERE id = $1`, id); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+func (r *postgresBookingRepository) Restore(id int) error {
+	tx, err := r.db.Beginx()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	var booking models.Booking
+	query := `SELECT id, car_id, start_rent, end_rent, status, driver_id FROM bookings
+        WHERE id = $1 AND deleted_at IS NOT NULL FOR UPDATE`
+	if err := tx.Get(&booking, query, id); err != nil {
+		return notFound(err)
+	}
+
+	if models.IsActiveBookingStatus(booking.Status) {
+		if err := reserveBookingResources(tx, &booking, id); err != nil {
+			return err
+		}
+	}
+
+	if _, err := tx.Exec(`UPDATE bookings SET deleted_at = NULL WHERE id = $1`, id); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// applyBookingPrice menyalin hasil perhitungan harga ke booking
+func applyBookingPrice(booking *models.Booking, price *services.PriceBreakdown) {
+	booking.TotalCost = price.TotalCost
+	booking.Discount = price.DiscountPercentage
+	booking.TotalDriverCost = price.TotalDriverCost
+	booking.DepositAmount = price.Deposit
+}
@@ -0,0 +1,190 @@
+package repository
+
+import (
+	"rental-mobil/models"
+	"rental-mobil/services"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// bookingStatusTimestamps memetakan status ke kolom waktu yang dicatat
+var bookingStatusTimestamps = map[string]string{
+	models.BookingStatusPickedUp:  "picked_up_at",
+	models.BookingStatusReturned:  "returned_at",
+	models.BookingStatusCancelled: "cancelled_at",
+	models.BookingStatusNoShow:    "no_show_at",
+}
+
+// Pickup hanya mengizinkan mobil dengan uang jaminan diambil setelah uang
+// jaminan ditahan penuh
+func (r *postgresBookingRepository) Pickup(id int) (*models.Booking, error) {
+	return r.transition(id, models.BookingStatusPickedUp, func(tx *sqlx.Tx, booking *models.Booking) error {
+		deposit, err := services.GetDepositSummary(tx, booking.ID)
+		if err != nil {
+			return err
+		}
+		if deposit.Required > 0 && deposit.Held < deposit.Required {
+			return &DepositRequiredError{Required: deposit.Required}
+		}
+		return nil
+	})
+}
+
+// Return mencatat tanggal pengembalian, denda keterlambatan pada booking dan
+// invoice, lalu insentif supir
+func (r *postgresBookingRepository) Return(id int, returnDate string, overrideBalance bool) (*models.Booking, error) {
+	return r.transition(id, models.BookingStatusReturned, func(tx *sqlx.Tx, booking *models.Booking) error {
+		if err := recordLateFee(tx, booking, returnDate); err != nil {
+			return err
+		}
+
+		// Sisa tagihan sudah termasuk denda keterlambatan di atas
+		balance, err := services.GetBookingBalance(tx, booking.ID)
+		if err != nil {
+			return err
+		}
+		if balance.BalanceDue > 0 && !overrideBalance {
+			return &OutstandingBalanceError{BalanceDue: balance.BalanceDue}
+		}
+
+		return recordDriverIncentive(tx, booking)
+	})
+}
+
+// Cancel menghitung biaya pembatalan dari kebijakan refund jenis booking,
+// mengganti isi invoice dengan biaya tersebut dan menyimpan totalnya
+// termasuk pajak ke booking
+func (r *postgresBookingRepository) Cancel(id int, reason, refundMethod string) (*models.Booking, error) {
+	return r.transition(id, models.BookingStatusCancelled, func(tx *sqlx.Tx, booking *models.Booking) error {
+		var policy models.CancellationPolicy
+		policyQuery := `SELECT cancellation_policy FROM booking_type WHERE id = $1`
+		if err := tx.Get(&policy, policyQuery, booking.BookingTypeID); err != nil {
+			return err
+		}
+
+		fee, err := services.CalculateCancellationFee(booking, policy, time.Now())
+		if err != nil {
+			return err
+		}
+
+		// Invoice kini hanya berisi biaya pembatalan; booking menyimpan
+		// totalnya termasuk pajak, yaitu nominal yang ditagihkan
+		booking.CancellationFee, err = services.SetInvoiceCancellationFee(tx, booking.ID, fee)
+		if err != nil {
+			return err
+		}
+		booking.CancelReason = &reason
+
+		updateQuery := `UPDATE bookings SET cancellation_fee = $1, cancellation_reason = $2 WHERE id = $3`
+		if _, err := tx.Exec(updateQuery, booking.CancellationFee, reason, booking.ID); err != nil {
+			return err
+		}
+
+		balance, err := services.GetBookingBalance(tx, booking.ID)
+		if err != nil {
+			return err
+		}
+		if balance.BalanceDue < 0 && refundMethod != "" {
+			refundQuery := `INSERT INTO payments (booking_id, type, method, amount, reference, note, paid_at)
+                VALUES ($1, $2, $3, $4, '', $5, NOW())`
+			_, err := tx.Exec(refundQuery, booking.ID, models.PaymentTypeRefund, refundMethod, -balance.BalanceDue, "Refund pembatalan")
+			if err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
+
+func (r *postgresBookingRepository) NoShow(id int) (*models.Booking, error) {
+	return r.transition(id, models.BookingStatusNoShow, nil)
+}
+
+func (r *postgresBookingRepository) Balance(id int) (*services.BookingBalance, error) {
+	return services.GetBookingBalance(r.db, id)
+}
+
+// transition mengunci booking, memindahkan statusnya lalu menjalankan
+// afterFn (jika ada) di transaksi yang sama sebelum commit
+func (r *postgresBookingRepository) transition(id int, to string, afterFn func(tx *sqlx.Tx, booking *models.Booking) error) (*models.Booking, error) {
+	tx, err := r.db.Beginx()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	var booking models.Booking
+	query := `SELECT id, customer_id, car_id, start_rent, end_rent, total_cost, status, booking_type_id, driver_id, total_driver_cost
+        FROM bookings WHERE id = $1 AND deleted_at IS NULL FOR UPDATE`
+	if err := tx.Get(&booking, query, id); err != nil {
+		return nil, notFound(err)
+	}
+
+	if !models.CanTransitionBooking(booking.Status, to) {
+		return nil, &TransitionError{From: booking.Status, To: to}
+	}
+
+	updateQuery := `UPDATE bookings SET status = $1, ` + bookingStatusTimestamps[to] + ` = NOW() WHERE id = $2`
+	if _, err := tx.Exec(updateQuery, to, booking.ID); err != nil {
+		return nil, err
+	}
+	booking.Status = to
+
+	if afterFn != nil {
+		if err := afterFn(tx, &booking); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return &booking, nil
+}
+
+// recordLateFee menyimpan tanggal pengembalian dan denda keterlambatan.
+// Tarif harian diambil dari sewa mobil ditambah biaya harian supir.
+func recordLateFee(tx *sqlx.Tx, booking *models.Booking, returnDate string) error {
+	var dailyRate models.Money
+	if err := tx.Get(&dailyRate, `SELECT daily_rent FROM cars WHERE id = $1`, booking.CarID); err != nil {
+		return err
+	}
+	if booking.DriverID != nil {
+		var driverCost models.Money
+		if err := tx.Get(&driverCost, `SELECT daily_cost FROM driver WHERE id = $1`, *booking.DriverID); err != nil {
+			return err
+		}
+		dailyRate += driverCost
+	}
+
+	lateDays, lateFee, err := services.CalculateLateFee(booking, returnDate, dailyRate)
+	if err != nil {
+		return err
+	}
+	booking.LateFee = lateFee
+	booking.ReturnDate = &returnDate
+
+	updateQuery := `UPDATE bookings SET return_date = $1, late_fee = $2 WHERE id = $3`
+	if _, err := tx.Exec(updateQuery, returnDate, booking.LateFee, booking.ID); err != nil {
+		return err
+	}
+
+	// Denda ikut dicantumkan pada invoice booking
+	return services.SetInvoiceLateFee(tx, booking.ID, lateDays, dailyRate, booking.LateFee)
+}
+
+// recordDriverIncentive mencatat insentif supir untuk booking yang selesai
+func recordDriverIncentive(tx *sqlx.Tx, booking *models.Booking) error {
+	if booking.DriverID == nil {
+		return nil
+	}
+
+	incentive, err := services.CalculateDriverIncentive(booking)
+	if err != nil {
+		return err
+	}
+	_, err = tx.Exec(`INSERT INTO driver_incentive (booking_id, incentive) VALUES ($1, $2)`, booking.ID, incentive)
+	return err
+}
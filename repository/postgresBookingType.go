@@ -0,0 +1,55 @@
+package repository
+
+import (
+	"rental-mobil/models"
+
+	"github.com/jmoiron/sqlx"
+)
+
+type postgresBookingTypeRepository struct {
+	db *sqlx.DB
+}
+
+func (r *postgresBookingTypeRepository) List(limit, offset int) ([]models.BookingType, error) {
+	bookingTypes := []models.BookingType{}
+	query := `SELECT id, name, description, requires_driver, surcharge_percentage, min_days, cancellation_policy
+        FROM booking_type ORDER BY id LIMIT $1 OFFSET $2`
+	err := r.db.Select(&bookingTypes, query, limit, offset)
+	return bookingTypes, err
+}
+
+func (r *postgresBookingTypeRepository) Get(id int) (*models.BookingType, error) {
+	var bookingType models.BookingType
+	query := `SELECT id, name, description, requires_driver, surcharge_percentage, min_days, cancellation_policy
+        FROM booking_type WHERE id = $1`
+	if err := r.db.Get(&bookingType, query, id); err != nil {
+		return nil, notFound(err)
+	}
+	return &bookingType, nil
+}
+
+func (r *postgresBookingTypeRepository) Create(bookingType *models.BookingType) error {
+	insertQuery := `INSERT INTO booking_type (name, description, requires_driver, surcharge_percentage, min_days, cancellation_policy)
+        VALUES ($1, $2, $3, $4, $5, $6) RETURNING id`
+	return r.db.Get(&bookingType.ID, insertQuery, bookingType.Name, bookingType.Description, bookingType.RequiresDriver,
+		bookingType.SurchargePercentage, bookingType.MinDays, bookingType.CancellationPolicy)
+}
+
+func (r *postgresBookingTypeRepository) Update(bookingType *models.BookingType) error {
+	query := `UPDATE booking_type SET name=$1, description=$2, requires_driver=$3, surcharge_percentage=$4, min_days=$5, cancellation_policy=$6 WHERE id=$7`
+	return requireAffected(r.db.Exec(query, bookingType.Name, bookingType.Description, bookingType.RequiresDriver,
+		bookingType.SurchargePercentage, bookingType.MinDays, bookingType.CancellationPolicy, bookingType.ID))
+}
+
+func (r *postgresBookingTypeRepository) Delete(id int) error {
+	// Tolak jika jenis booking masih dipakai booking
+	var count int
+	if err := r.db.Get(&count, `SELECT COUNT(*) FROM bookings WHERE booking_type_id = $1`, id); err != nil {
+		return err
+	}
+	if count > 0 {
+		return ErrInUse
+	}
+
+	return requireAffected(r.db.Exec(`DELETE FROM booking_type WHERE id=$1`, id))
+}
@@ -0,0 +1,76 @@
+package repository
+
+import (
+	"rental-mobil/models"
+
+	"github.com/jmoiron/sqlx"
+)
+
+type postgresCarRepository struct {
+	db *sqlx.DB
+}
+
+func (r *postgresCarRepository) List(limit, offset int, includeDeleted bool) ([]models.Car, error) {
+	cars := []models.Car{}
+	query := `SELECT id, name, stock, daily_rent, deposit_amount, deleted_at FROM cars
+        WHERE $3 OR deleted_at IS NULL
+        ORDER BY id
+        LIMIT $1 OFFSET $2`
+	err := r.db.Select(&cars, query, limit, offset, includeDeleted)
+	return cars, err
+}
+
+func (r *postgresCarRepository) ListActive() ([]models.Car, error) {
+	cars := []models.Car{}
+	query := `SELECT id, name, stock, daily_rent, deposit_amount FROM cars WHERE deleted_at IS NULL ORDER BY id`
+	err := r.db.Select(&cars, query)
+	return cars, err
+}
+
+func (r *postgresCarRepository) Get(id int) (*models.Car, error) {
+	var car models.Car
+	query := `SELECT id, name, stock, daily_rent, deposit_amount FROM cars WHERE id = $1 AND deleted_at IS NULL`
+	if err := r.db.Get(&car, query, id); err != nil {
+		return nil, notFound(err)
+	}
+	return &car, nil
+}
+
+func (r *postgresCarRepository) Create(car *models.Car) error {
+	insertQuery := `INSERT INTO cars (name, stock, daily_rent, deposit_amount) VALUES ($1, $2, $3, $4) RETURNING id`
+	return r.db.Get(&car.ID, insertQuery, car.Name, car.Stock, car.DailyRent, car.DepositAmount)
+}
+
+func (r *postgresCarRepository) Update(car *models.Car) error {
+	query := `UPDATE cars SET name=$1, stock=$2, daily_rent=$3, deposit_amount=$4 WHERE id=$5 AND deleted_at IS NULL`
+	return requireAffected(r.db.Exec(query, car.Name, car.Stock, car.DailyRent, car.DepositAmount, car.ID))
+}
+
+func (r *postgresCarRepository) Delete(id int, force bool) ([]int, error) {
+	tx, err := r.db.Beginx()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	var carID int
+	if err := tx.Get(&carID, `SELECT id FROM cars WHERE id = $1 AND deleted_at IS NULL FOR UPDATE`, id); err != nil {
+		return nil, notFound(err)
+	}
+
+	cancelled, err := cancelBlockingBookings(tx, "car_id", id, force, "Mobil dihapus")
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := tx.Exec(`UPDATE cars SET deleted_at = NOW() WHERE id = $1`, id); err != nil {
+		return nil, err
+	}
+
+	return cancelled, tx.Commit()
+}
+
+func (r *postgresCarRepository) Restore(id int) error {
+	query := `UPDATE cars SET deleted_at = NULL WHERE id=$1 AND deleted_at IS NOT NULL`
+	return requireAffected(r.db.Exec(query, id))
+}
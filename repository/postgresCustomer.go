@@ -0,0 +1,193 @@
+package repository
+
+import (
+	"rental-mobil/models"
+	"rental-mobil/services"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+)
+
+type postgresCustomerRepository struct {
+	db *sqlx.DB
+}
+
+func (r *postgresCustomerRepository) List(limit, offset int, includeDeleted bool) ([]models.Customer, error) {
+	customers := []models.Customer{}
+	query := `SELECT * FROM customers WHERE $3 OR deleted_at IS NULL ORDER BY id LIMIT $1 OFFSET $2`
+	err := r.db.Select(&customers, query, limit, offset, includeDeleted)
+	return customers, err
+}
+
+func (r *postgresCustomerRepository) Get(id int) (*models.Customer, error) {
+	var customer models.Customer
+	if err := r.db.Get(&customer, `SELECT * FROM customers WHERE id = $1 AND deleted_at IS NULL`, id); err != nil {
+		return nil, notFound(err)
+	}
+	return &customer, nil
+}
+
+func (r *postgresCustomerRepository) Exists(id int) (bool, error) {
+	var count int
+	err := r.db.Get(&count, `SELECT COUNT(*) FROM customers WHERE id = $1 AND deleted_at IS NULL`, id)
+	return count > 0, err
+}
+
+func (r *postgresCustomerRepository) NIKExists(nik string, excludeID int) (bool, error) {
+	var count int
+	err := r.db.Get(&count, `SELECT COUNT(*) FROM customers WHERE nik = $1 AND id != $2`, nik, excludeID)
+	return count > 0, err
+}
+
+func (r *postgresCustomerRepository) Create(customer *models.Customer) error {
+	tx, err := r.db.Beginx()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	insertQuery := `INSERT INTO customers (name, nik, phone, membership_id) VALUES ($1, $2, $3, $4) RETURNING id`
+	err = tx.Get(&customer.ID, insertQuery, customer.Name, customer.NIK, customer.Phone, customer.MembershipID)
+	if err != nil {
+		return err
+	}
+
+	// Catat membership awal ke riwayat
+	if customer.MembershipID != nil {
+		err = insertMembershipHistory(tx, customer.ID, nil, customer.MembershipID, time.Now().Format("2006-01-02"))
+		if err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+func (r *postgresCustomerRepository) Update(customer *models.Customer) error {
+	tx, err := r.db.Beginx()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	// Update hanya field yang dikirim; field kosong tidak menimpa data lama
+	query := `UPDATE customers SET
+        name = COALESCE(NULLIF($1, ''), name),
+        nik = COALESCE(NULLIF($2, ''), nik),
+        phone = COALESCE(NULLIF($3, ''), phone)
+        WHERE id = $4 AND deleted_at IS NULL`
+	if err := requireAffected(tx.Exec(query, customer.Name, customer.NIK, customer.Phone, customer.ID)); err != nil {
+		return err
+	}
+
+	// Perubahan membership ikut dicatat ke riwayat
+	if customer.MembershipID != nil {
+		err = changeCustomerMembership(tx, customer.ID, customer.MembershipID, time.Now().Format("2006-01-02"))
+		if err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+func (r *postgresCustomerRepository) ChangeMembership(id int, membershipID *int, effectiveDate string) error {
+	tx, err := r.db.Beginx()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := changeCustomerMembership(tx, id, membershipID, effectiveDate); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func (r *postgresCustomerRepository) MembershipHistory(id int) ([]models.MembershipHistory, error) {
+	history := []models.MembershipHistory{}
+	query := `SELECT id, customer_id, old_membership_id, new_membership_id, effective_date, created_at
+        FROM membership_history WHERE customer_id = $1 ORDER BY effective_date, id`
+	err := r.db.Select(&history, query, id)
+	return history, err
+}
+
+func (r *postgresCustomerRepository) RentalStats(since string) ([]services.CustomerRentalStats, error) {
+	stats := []services.CustomerRentalStats{}
+	query := `SELECT c.id AS customer_id, c.membership_id,
+            COUNT(b.id) AS rentals,
+            COALESCE(SUM(b.total_cost + b.total_driver_cost + b.late_fee), 0) AS spend,
+            COALESCE(SUM(b.end_rent::date - b.start_rent::date), 0) AS days
+        FROM customers c
+        LEFT JOIN bookings b ON b.customer_id = c.id AND b.status = 'returned' AND b.end_rent >= $1
+            AND b.deleted_at IS NULL
+        WHERE c.deleted_at IS NULL
+        GROUP BY c.id, c.membership_id
+        ORDER BY c.id`
+	err := r.db.Select(&stats, query, since)
+	return stats, err
+}
+
+func (r *postgresCustomerRepository) Delete(id int, force bool) ([]int, error) {
+	tx, err := r.db.Beginx()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	var customerID int
+	checkQuery := `SELECT id FROM customers WHERE id = $1 AND deleted_at IS NULL FOR UPDATE`
+	if err := tx.Get(&customerID, checkQuery, id); err != nil {
+		return nil, notFound(err)
+	}
+
+	cancelled, err := cancelBlockingBookings(tx, "customer_id", id, force, "Pelanggan dihapus")
+	if err != nil {
+		return nil, err
+	}
+
+	// Soft delete agar booking dan riwayat membership pelanggan tetap utuh
+	if _, err := tx.Exec(`UPDATE customers SET deleted_at = NOW() WHERE id = $1`, id); err != nil {
+		return nil, err
+	}
+
+	return cancelled, tx.Commit()
+}
+
+func (r *postgresCustomerRepository) Restore(id int) error {
+	query := `UPDATE customers SET deleted_at = NULL WHERE id=$1 AND deleted_at IS NOT NULL`
+	return requireAffected(r.db.Exec(query, id))
+}
+
+// changeCustomerMembership mengganti membership_id pelanggan dan mencatat
+// riwayatnya jika memang berubah
+func changeCustomerMembership(tx *sqlx.Tx, customerID int, membershipID *int, effectiveDate string) error {
+	var current *int
+	err := tx.Get(&current, `SELECT membership_id FROM customers WHERE id = $1 AND deleted_at IS NULL FOR UPDATE`, customerID)
+	if err != nil {
+		return notFound(err)
+	}
+
+	if current == nil && membershipID == nil {
+		return nil
+	}
+	if current != nil && membershipID != nil && *current == *membershipID {
+		return nil
+	}
+
+	_, err = tx.Exec(`UPDATE customers SET membership_id = $1 WHERE id = $2`, membershipID, customerID)
+	if err != nil {
+		return err
+	}
+
+	return insertMembershipHistory(tx, customerID, current, membershipID, effectiveDate)
+}
+
+// insertMembershipHistory menyimpan satu baris riwayat membership
+func insertMembershipHistory(tx *sqlx.Tx, customerID int, oldID, newID *int, effectiveDate string) error {
+	query := `INSERT INTO membership_history (customer_id, old_membership_id, new_membership_id, effective_date, created_at)
+        VALUES ($1, $2, $3, $4, NOW())`
+	_, err := tx.Exec(query, customerID, oldID, newID, effectiveDate)
+	return err
+}
@@ -0,0 +1,92 @@
+package repository
+
+import (
+	"rental-mobil/models"
+	"strconv"
+
+	"github.com/jmoiron/sqlx"
+)
+
+type postgresDriverRepository struct {
+	db *sqlx.DB
+}
+
+func (r *postgresDriverRepository) List(limit, offset int) ([]models.Driver, error) {
+	drivers := []models.Driver{}
+	query := `SELECT id, name, nik, phone_number, daily_cost FROM driver ORDER BY id LIMIT $1 OFFSET $2`
+	err := r.db.Select(&drivers, query, limit, offset)
+	return drivers, err
+}
+
+func (r *postgresDriverRepository) Get(id int) (*models.Driver, error) {
+	var driver models.Driver
+	query := `SELECT id, name, nik, phone_number, daily_cost FROM driver WHERE id = $1`
+	if err := r.db.Get(&driver, query, id); err != nil {
+		return nil, notFound(err)
+	}
+	return &driver, nil
+}
+
+func (r *postgresDriverRepository) NIKExists(nik string, excludeID int) (bool, error) {
+	var count int
+	err := r.db.Get(&count, `SELECT COUNT(*) FROM driver WHERE nik = $1 AND id != $2`, nik, excludeID)
+	return count > 0, err
+}
+
+func (r *postgresDriverRepository) Create(driver *models.Driver) error {
+	insertQuery := `INSERT INTO driver (name, nik, phone_number, daily_cost) VALUES ($1, $2, $3, $4) RETURNING id`
+	return r.db.Get(&driver.ID, insertQuery, driver.Name, driver.NIK, driver.PhoneNumber, driver.DailyCost)
+}
+
+func (r *postgresDriverRepository) Update(driver *models.Driver) error {
+	query := `UPDATE driver SET name=$1, nik=$2, phone_number=$3, daily_cost=$4 WHERE id=$5`
+	return requireAffected(r.db.Exec(query, driver.Name, driver.NIK, driver.PhoneNumber, driver.DailyCost, driver.ID))
+}
+
+func (r *postgresDriverRepository) Delete(id int) error {
+	// Tolak jika supir masih tercatat di booking, termasuk booking yang
+	// sudah selesai karena insentifnya dihitung dari booking tersebut
+	var count int
+	if err := r.db.Get(&count, `SELECT COUNT(*) FROM bookings WHERE driver_id = $1`, id); err != nil {
+		return err
+	}
+	if count > 0 {
+		return ErrInUse
+	}
+
+	return requireAffected(r.db.Exec(`DELETE FROM driver WHERE id=$1`, id))
+}
+
+func (r *postgresDriverRepository) ListAvailable(start, end string) ([]models.Driver, error) {
+	drivers := []models.Driver{}
+	query := `SELECT d.id, d.name, d.nik, d.phone_number, d.daily_cost FROM driver d
+        WHERE NOT EXISTS (
+            SELECT 1 FROM bookings b
+            WHERE b.driver_id = d.id AND b.status IN ('reserved', 'picked_up')
+            AND b.deleted_at IS NULL AND b.start_rent < $2 AND b.end_rent > $1
+        )
+        ORDER BY d.id`
+	err := r.db.Select(&drivers, query, start, end)
+	return drivers, err
+}
+
+func (r *postgresDriverRepository) Incentives(id int, from, to string) ([]models.DriverIncentive, error) {
+	query := `SELECT di.id, di.booking_id, di.incentive FROM driver_incentive di
+        JOIN bookings b ON b.id = di.booking_id
+        WHERE b.driver_id = $1`
+	args := []interface{}{id}
+
+	if from != "" {
+		args = append(args, from)
+		query += ` AND b.end_rent >= $` + strconv.Itoa(len(args))
+	}
+	if to != "" {
+		args = append(args, to)
+		query += ` AND b.end_rent <= $` + strconv.Itoa(len(args))
+	}
+	query += ` ORDER BY b.end_rent, di.id`
+
+	incentives := []models.DriverIncentive{}
+	err := r.db.Select(&incentives, query, args...)
+	return incentives, err
+}
@@ -0,0 +1,63 @@
+package repository
+
+import (
+	"rental-mobil/models"
+
+	"github.com/jmoiron/sqlx"
+)
+
+type postgresMembershipRepository struct {
+	db *sqlx.DB
+}
+
+func (r *postgresMembershipRepository) List(limit, offset int) ([]models.Membership, error) {
+	memberships := []models.Membership{}
+	query := `SELECT id, name, discount, min_rentals, min_spend, min_days FROM membership ORDER BY id LIMIT $1 OFFSET $2`
+	err := r.db.Select(&memberships, query, limit, offset)
+	return memberships, err
+}
+
+func (r *postgresMembershipRepository) ListTiers() ([]models.Membership, error) {
+	tiers := []models.Membership{}
+	query := `SELECT id, name, discount, min_rentals, min_spend, min_days FROM membership ORDER BY discount DESC, id`
+	err := r.db.Select(&tiers, query)
+	return tiers, err
+}
+
+func (r *postgresMembershipRepository) Get(id int) (*models.Membership, error) {
+	var membership models.Membership
+	query := `SELECT id, name, discount, min_rentals, min_spend, min_days FROM membership WHERE id = $1`
+	if err := r.db.Get(&membership, query, id); err != nil {
+		return nil, notFound(err)
+	}
+	return &membership, nil
+}
+
+func (r *postgresMembershipRepository) Exists(id int) (bool, error) {
+	var count int
+	err := r.db.Get(&count, `SELECT COUNT(*) FROM membership WHERE id = $1`, id)
+	return count > 0, err
+}
+
+func (r *postgresMembershipRepository) Create(membership *models.Membership) error {
+	insertQuery := `INSERT INTO membership (name, discount, min_rentals, min_spend, min_days) VALUES ($1, $2, $3, $4, $5) RETURNING id`
+	return r.db.Get(&membership.ID, insertQuery, membership.Name, membership.Discount, membership.MinRentals, membership.MinSpend, membership.MinDays)
+}
+
+func (r *postgresMembershipRepository) Update(membership *models.Membership) error {
+	query := `UPDATE membership SET name=$1, discount=$2, min_rentals=$3, min_spend=$4, min_days=$5 WHERE id=$6`
+	return requireAffected(r.db.Exec(query, membership.Name, membership.Discount, membership.MinRentals, membership.MinSpend, membership.MinDays, membership.ID))
+}
+
+func (r *postgresMembershipRepository) Delete(id int) error {
+	// Tolak jika masih ada pelanggan dengan membership ini
+	var count int
+	if err := r.db.Get(&count, `SELECT COUNT(*) FROM customers WHERE membership_id = $1`, id); err != nil {
+		return err
+	}
+	if count > 0 {
+		return ErrInUse
+	}
+
+	return requireAffected(r.db.Exec(`DELETE FROM membership WHERE id=$1`, id))
+}
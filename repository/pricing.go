@@ -0,0 +1,38 @@
+package repository
+
+import (
+	"rental-mobil/models"
+	"rental-mobil/services"
+)
+
+// Repositories memenuhi services.PricingSource sehingga harga booking dihitung
+// dari repository yang sama dengan tempat booking disimpan
+var _ services.PricingSource = (*Repositories)(nil)
+
+func (r *Repositories) BookingType(id int) (*models.BookingType, error) {
+	return r.BookingTypes.Get(id)
+}
+
+func (r *Repositories) Car(id int) (*models.Car, error) {
+	return r.Cars.Get(id)
+}
+
+func (r *Repositories) MembershipDiscount(customerID int) (float64, error) {
+	customer, err := r.Customers.Get(customerID)
+	if err != nil {
+		return 0, err
+	}
+	if customer.MembershipID == nil {
+		return 0, nil
+	}
+
+	membership, err := r.Memberships.Get(*customer.MembershipID)
+	if err != nil {
+		return 0, err
+	}
+	return membership.Discount, nil
+}
+
+func (r *Repositories) Driver(id int) (*models.Driver, error) {
+	return r.Drivers.Get(id)
+}
@@ -0,0 +1,170 @@
+package repository
+
+import (
+	"errors"
+	"rental-mobil/models"
+	"rental-mobil/services"
+)
+
+var (
+	// ErrNotFound dikembalikan jika data tidak ada atau sudah dihapus
+	ErrNotFound = errors.New("record not found")
+	// ErrInUse dikembalikan jika data masih dirujuk data lain
+	ErrInUse = errors.New("record is still in use")
+	// ErrBookingNotActive dikembalikan saat mengubah booking yang sudah selesai atau dibatalkan
+	ErrBookingNotActive = errors.New("booking is no longer active")
+	// ErrBookingActive dikembalikan saat menghapus booking yang masih reserved atau picked_up
+	ErrBookingActive = errors.New("booking is still active")
+	// ErrCarFullyBooked dikembalikan jika stok mobil habis pada rentang sewa
+	ErrCarFullyBooked = errors.New("car is fully booked")
+	// ErrDriverUnavailable dikembalikan jika supir sudah bertugas pada rentang sewa
+	ErrDriverUnavailable = errors.New("driver is already assigned")
+)
+
+// BlockedError dikembalikan saat mobil atau pelanggan tidak bisa dihapus
+// karena masih memiliki booking yang belum selesai. PickedUp true jika ada
+// mobil yang sedang disewa sehingga force pun tidak bisa membatalkannya.
+type BlockedError struct {
+	Bookings []models.BlockingBooking
+	PickedUp bool
+}
+
+func (e *BlockedError) Error() string {
+	return "record has active bookings"
+}
+
+// TransitionError dikembalikan jika status booking tidak boleh berpindah
+// dari From ke To
+type TransitionError struct {
+	From string
+	To   string
+}
+
+func (e *TransitionError) Error() string {
+	return "cannot change booking status from " + e.From + " to " + e.To
+}
+
+// DepositRequiredError dikembalikan saat mobil akan diambil sebelum uang
+// jaminan sebesar Required ditahan penuh
+type DepositRequiredError struct {
+	Required models.Money
+}
+
+func (e *DepositRequiredError) Error() string {
+	return "security deposit must be held before pickup"
+}
+
+// OutstandingBalanceError dikembalikan saat mobil dikembalikan padahal
+// booking masih memiliki sisa tagihan
+type OutstandingBalanceError struct {
+	BalanceDue models.Money
+}
+
+func (e *OutstandingBalanceError) Error() string {
+	return "booking has an outstanding balance"
+}
+
+// CarRepository menyimpan data mobil
+type CarRepository interface {
+	List(limit, offset int, includeDeleted bool) ([]models.Car, error)
+	ListActive() ([]models.Car, error)
+	Get(id int) (*models.Car, error)
+	Create(car *models.Car) error
+	Update(car *models.Car) error
+	// Delete melakukan soft delete. Booking yang belum selesai menghalangi
+	// penghapusan kecuali force true, yang membatalkannya di transaksi yang
+	// sama. Mengembalikan id booking yang dibatalkan.
+	Delete(id int, force bool) ([]int, error)
+	Restore(id int) error
+}
+
+// CustomerRepository menyimpan data pelanggan beserta riwayat membershipnya
+type CustomerRepository interface {
+	List(limit, offset int, includeDeleted bool) ([]models.Customer, error)
+	Get(id int) (*models.Customer, error)
+	Exists(id int) (bool, error)
+	NIKExists(nik string, excludeID int) (bool, error)
+	// Create menyimpan pelanggan dan mencatat membership awalnya ke riwayat
+	Create(customer *models.Customer) error
+	// Update mengganti name, nik dan phone; membership_id yang diisi ikut
+	// dicatat ke riwayat
+	Update(customer *models.Customer) error
+	ChangeMembership(id int, membershipID *int, effectiveDate string) error
+	MembershipHistory(id int) ([]models.MembershipHistory, error)
+	RentalStats(since string) ([]services.CustomerRentalStats, error)
+	Delete(id int, force bool) ([]int, error)
+	Restore(id int) error
+}
+
+// BookingRepository menyimpan data booking. Create dan Update mengunci stok
+// mobil dan jadwal supir lalu menyimpan invoice dari rincian harga.
+type BookingRepository interface {
+	List(limit, offset int, includeDeleted bool) ([]models.Booking, int, error)
+	CountOverlapping(carID int, start, end string) (int, error)
+	CountDriverOverlapping(driverID int, start, end string) (int, error)
+	Create(booking *models.Booking, price *services.PriceBreakdown) error
+	Update(booking *models.Booking, price *services.PriceBreakdown) error
+	// Delete melakukan soft delete dan menolak dengan ErrBookingActive jika
+	// booking belum dibatalkan atau dikembalikan
+	Delete(id int) error
+	// Restore memulihkan booking yang di-soft delete. Booking aktif hanya
+	// dipulihkan jika stok mobil dan supirnya masih tersedia.
+	Restore(id int) error
+	// Pickup, Return, Cancel dan NoShow memindahkan status booking beserta
+	// denda, biaya pembatalan, insentif supir dan refund yang menyertainya
+	// secara atomik. Perpindahan yang tidak sah ditolak dengan TransitionError.
+	Pickup(id int) (*models.Booking, error)
+	// Return menolak dengan OutstandingBalanceError jika masih ada sisa
+	// tagihan setelah denda, kecuali overrideBalance true
+	Return(id int, returnDate string, overrideBalance bool) (*models.Booking, error)
+	// Cancel mencatat kelebihan pembayaran sebagai refund jika refundMethod diisi
+	Cancel(id int, reason, refundMethod string) (*models.Booking, error)
+	NoShow(id int) (*models.Booking, error)
+	Balance(id int) (*services.BookingBalance, error)
+}
+
+// DriverRepository menyimpan data supir dan insentifnya
+type DriverRepository interface {
+	List(limit, offset int) ([]models.Driver, error)
+	Get(id int) (*models.Driver, error)
+	NIKExists(nik string, excludeID int) (bool, error)
+	Create(driver *models.Driver) error
+	Update(driver *models.Driver) error
+	Delete(id int) error
+	ListAvailable(start, end string) ([]models.Driver, error)
+	// Incentives memfilter berdasarkan end_rent booking; from dan to kosong
+	// berarti tanpa batas
+	Incentives(id int, from, to string) ([]models.DriverIncentive, error)
+}
+
+// MembershipRepository menyimpan tingkat membership
+type MembershipRepository interface {
+	List(limit, offset int) ([]models.Membership, error)
+	// ListTiers mengurutkan membership dari diskon tertinggi
+	ListTiers() ([]models.Membership, error)
+	Get(id int) (*models.Membership, error)
+	Exists(id int) (bool, error)
+	Create(membership *models.Membership) error
+	Update(membership *models.Membership) error
+	Delete(id int) error
+}
+
+// BookingTypeRepository menyimpan jenis booking beserta aturan harganya
+type BookingTypeRepository interface {
+	List(limit, offset int) ([]models.BookingType, error)
+	Get(id int) (*models.BookingType, error)
+	Create(bookingType *models.BookingType) error
+	Update(bookingType *models.BookingType) error
+	// Delete menolak dengan ErrInUse jika jenis booking masih dipakai booking
+	Delete(id int) error
+}
+
+// Repositories mengelompokkan repository per agregat yang diteruskan ke controller
+type Repositories struct {
+	Cars         CarRepository
+	Customers    CustomerRepository
+	Bookings     BookingRepository
+	Drivers      DriverRepository
+	Memberships  MembershipRepository
+	BookingTypes BookingTypeRepository
+}
@@ -0,0 +1,12 @@
+package routes
+
+import (
+	"rental-mobil/controllers"
+
+	"github.com/labstack/echo/v4"
+)
+
+// RegisterAdminRoutes untuk menangani rute administrasi
+func RegisterAdminRoutes(e *echo.Echo, h *controllers.Handler) {
+	e.POST("/admin/memberships/evaluate", h.EvaluateMembershipTiers)
+}
@@ -5,9 +5,32 @@ import (
     "github.com/labstack/echo/v4"
 )
 
-func BookingRoutes(e *echo.Echo) {
-    e.GET("/bookings", controllers.GetAllBookings)
-    e.POST("/bookings", controllers.CreateBooking)
-    e.PUT("/bookings/:id", controllers.UpdateBooking)
-    e.DELETE("/bookings/:id", controllers.DeleteBooking)
+func BookingRoutes(e *echo.Echo, h *controllers.Handler) {
+    e.GET("/bookings", h.GetAllBookings)
+    e.POST("/bookings", h.CreateBooking)
+    e.POST("/bookings/quote", h.QuoteBooking)
+    e.PUT("/bookings/:id", h.UpdateBooking)
+    e.DELETE("/bookings/:id", h.DeleteBooking)
+    e.POST("/bookings/:id/restore", h.RestoreBooking)
+
+    // Invoice booking
+    e.GET("/bookings/:id/invoice", h.GetBookingInvoice)
+    e.GET("/bookings/:id/invoice/html", h.GetBookingInvoiceHTML)
+    e.GET("/bookings/:id/invoice/pdf", h.GetBookingInvoicePDF)
+
+    // Pembayaran booking
+    e.GET("/bookings/:id/payments", h.GetBookingPayments)
+    e.POST("/bookings/:id/payments", h.CreateBookingPayment)
+
+    // Uang jaminan booking
+    e.GET("/bookings/:id/deposit", h.GetBookingDeposit)
+    e.POST("/bookings/:id/deposit/hold", h.HoldBookingDeposit)
+    e.POST("/bookings/:id/deposit/deductions", h.DeductBookingDeposit)
+    e.POST("/bookings/:id/deposit/release", h.ReleaseBookingDeposit)
+
+    // Perpindahan status booking
+    e.POST("/bookings/:id/pickup", h.PickupBooking)
+    e.POST("/bookings/:id/return", h.ReturnBooking)
+    e.POST("/bookings/:id/cancel", h.CancelBooking)
+    e.POST("/bookings/:id/no-show", h.NoShowBooking)
 }
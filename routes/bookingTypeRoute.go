@@ -0,0 +1,16 @@
+package routes
+
+import (
+	"rental-mobil/controllers"
+
+	"github.com/labstack/echo/v4"
+)
+
+// RegisterBookingTypeRoutes untuk menangani rute jenis booking
+func RegisterBookingTypeRoutes(e *echo.Echo, h *controllers.Handler) {
+	e.GET("/booking-types", h.GetAllBookingTypes)
+	e.GET("/booking-types/:id", h.GetBookingType)
+	e.POST("/booking-types", h.CreateBookingType)
+	e.PUT("/booking-types/:id", h.UpdateBookingType)
+	e.DELETE("/booking-types/:id", h.DeleteBookingType)
+}
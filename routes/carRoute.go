@@ -6,9 +6,12 @@ import (
 )
 
 // RegisterCarRoutes untuk menangani rute mobil
-func RegisterCarRoutes(e *echo.Echo) {
-	e.GET("/cars", controllers.GetAllCars)
-	e.POST("/cars", controllers.CreateCar)
-	e.PUT("/cars/:id", controllers.UpdateCar)
-	e.DELETE("/cars/:id", controllers.DeleteCar)
+func RegisterCarRoutes(e *echo.Echo, h *controllers.Handler) {
+	e.GET("/cars", h.GetAllCars)
+	e.GET("/cars/available", h.GetAvailableCars)
+	e.GET("/cars/:id/availability", h.GetCarAvailability)
+	e.POST("/cars", h.CreateCar)
+	e.PUT("/cars/:id", h.UpdateCar)
+	e.DELETE("/cars/:id", h.DeleteCar)
+	e.POST("/cars/:id/restore", h.RestoreCar)
 }
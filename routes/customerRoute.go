@@ -7,9 +7,12 @@ import (
 )
 
 // RegisterCustomerRoutes untuk menangani rute pelanggan
-func RegisterCustomerRoutes(e *echo.Echo) {
-	e.GET("/customers", controllers.GetAllCustomers)
-	e.POST("/customers", controllers.CreateCustomer)
-	e.PUT("/customers/:id", controllers.UpdateCustomer)
-	e.DELETE("/customers/:id", controllers.DeleteCustomer)
+func RegisterCustomerRoutes(e *echo.Echo, h *controllers.Handler) {
+	e.GET("/customers", h.GetAllCustomers)
+	e.POST("/customers", h.CreateCustomer)
+	e.PUT("/customers/:id", h.UpdateCustomer)
+	e.DELETE("/customers/:id", h.DeleteCustomer)
+	e.POST("/customers/:id/restore", h.RestoreCustomer)
+	e.PUT("/customers/:id/membership", h.UpdateCustomerMembership)
+	e.GET("/customers/:id/membership-history", h.GetCustomerMembershipHistory)
 }
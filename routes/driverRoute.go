@@ -0,0 +1,18 @@
+package routes
+
+import (
+	"rental-mobil/controllers"
+
+	"github.com/labstack/echo/v4"
+)
+
+// RegisterDriverRoutes untuk menangani rute supir
+func RegisterDriverRoutes(e *echo.Echo, h *controllers.Handler) {
+	e.GET("/drivers", h.GetAllDrivers)
+	e.GET("/drivers/available", h.GetAvailableDrivers)
+	e.GET("/drivers/:id", h.GetDriver)
+	e.GET("/drivers/:id/incentives", h.GetDriverIncentives)
+	e.POST("/drivers", h.CreateDriver)
+	e.PUT("/drivers/:id", h.UpdateDriver)
+	e.DELETE("/drivers/:id", h.DeleteDriver)
+}
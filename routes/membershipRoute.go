@@ -0,0 +1,15 @@
+package routes
+
+import (
+	"rental-mobil/controllers"
+
+	"github.com/labstack/echo/v4"
+)
+
+// RegisterMembershipRoutes untuk menangani rute membership
+func RegisterMembershipRoutes(e *echo.Echo, h *controllers.Handler) {
+	e.GET("/memberships", h.GetAllMemberships)
+	e.POST("/memberships", h.CreateMembership)
+	e.PUT("/memberships/:id", h.UpdateMembership)
+	e.DELETE("/memberships/:id", h.DeleteMembership)
+}
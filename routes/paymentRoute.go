@@ -0,0 +1,20 @@
+package routes
+
+import (
+	"rental-mobil/controllers"
+
+	"github.com/labstack/echo/v4"
+)
+
+// RegisterPaymentRoutes untuk menangani rute pembayaran online
+func RegisterPaymentRoutes(e *echo.Echo, h *controllers.Handler) {
+	e.POST("/bookings/:id/charges", h.CreateBookingCharge)
+	e.POST("/charges/:id/refund", h.RefundCharge)
+	e.POST("/payments/webhook/:provider", h.PaymentWebhook)
+}
+
+// RegisterFakeGatewayRoutes untuk halaman bayar gateway palsu. Hanya
+// didaftarkan jika FAKE_GATEWAY_ENABLED aktif.
+func RegisterFakeGatewayRoutes(e *echo.Echo, h *controllers.Handler) {
+	e.POST("/fake-gateway/charges/:id/pay", h.SimulateFakePayment)
+}
@@ -0,0 +1,59 @@
+package services
+
+import (
+	"rental-mobil/config"
+	"rental-mobil/models"
+	"time"
+)
+
+// CalculateLateFee menghitung hari terlambat dan denda jika mobil
+// dikembalikan setelah end_rent. Denda = hari terlambat x tarif harian
+// (sewa mobil + biaya supir) x pengali denda.
+func CalculateLateFee(booking *models.Booking, returnDate string, dailyRate models.Money) (int, models.Money, error) {
+	endRent, err := ParseRentDate(booking.EndRent)
+	if err != nil {
+		return 0, 0, err
+	}
+	returned, err := ParseRentDate(returnDate)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	lateDays := 0
+	if returned.After(endRent) {
+		lateDays = int(returned.Sub(endRent).Hours() / 24)
+	}
+	return lateDays, dailyRate.Times(lateDays).Multiply(config.GetLateFeeMultiplier()), nil
+}
+
+// CalculateCancellationFee menghitung bagian harga sewa dan supir yang tidak
+// direfund menurut kebijakan jenis booking, berdasarkan jarak hari dari
+// today ke start_rent. Nominalnya belum termasuk pajak; pajak ditambahkan
+// oleh invoice.
+func CalculateCancellationFee(booking *models.Booking, policy models.CancellationPolicy, today time.Time) (models.Money, error) {
+	startRent, err := ParseRentDate(booking.StartRent)
+	if err != nil {
+		return 0, err
+	}
+	today, _ = time.Parse("2006-01-02", today.Format("2006-01-02"))
+	daysBefore := int(startRent.Sub(today).Hours() / 24)
+
+	refundPercentage := policy.RefundPercentage(daysBefore)
+	return (booking.TotalCost + booking.TotalDriverCost).Percent(100 - refundPercentage), nil
+}
+
+// CalculateDriverIncentive menghitung insentif supir untuk booking yang
+// selesai menurut aturan insentif yang aktif
+func CalculateDriverIncentive(booking *models.Booking) (models.Money, error) {
+	startRent, err := ParseRentDate(booking.StartRent)
+	if err != nil {
+		return 0, err
+	}
+	endRent, err := ParseRentDate(booking.EndRent)
+	if err != nil {
+		return 0, err
+	}
+	duration := int(endRent.Sub(startRent).Hours() / 24)
+
+	return config.GetDriverIncentiveRule().Calculate(booking.TotalCost, duration), nil
+}
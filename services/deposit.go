@@ -0,0 +1,45 @@
+package services
+
+import (
+	"rental-mobil/models"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// DepositSummary merangkum uang jaminan sebuah booking
+type DepositSummary struct {
+	Required  models.Money `json:"required"`  // Uang jaminan yang ditetapkan mobil
+	Held      models.Money `json:"held"`      // Uang jaminan yang diterima
+	Deducted  models.Money `json:"deducted"`  // Total potongan
+	Released  models.Money `json:"released"`  // Sudah dikembalikan ke pelanggan
+	Remaining models.Money `json:"remaining"` // Masih ditahan
+}
+
+// GetDepositSummary menghitung posisi uang jaminan booking
+func GetDepositSummary(q sqlx.Queryer, bookingID int) (*DepositSummary, error) {
+	var summary DepositSummary
+	if err := sqlx.Get(q, &summary.Required, `SELECT deposit_amount FROM bookings WHERE id = $1`, bookingID); err != nil {
+		return nil, err
+	}
+
+	var totals struct {
+		Held     models.Money `db:"held"`
+		Deducted models.Money `db:"deducted"`
+		Released models.Money `db:"released"`
+	}
+	query := `SELECT
+            COALESCE(SUM(amount) FILTER (WHERE type = $2), 0) AS held,
+            COALESCE(SUM(amount) FILTER (WHERE type = $3), 0) AS deducted,
+            COALESCE(SUM(amount) FILTER (WHERE type = $4), 0) AS released
+        FROM deposit_transactions WHERE booking_id = $1`
+	err := sqlx.Get(q, &totals, query, bookingID, models.DepositHold, models.DepositDeduction, models.DepositRelease)
+	if err != nil {
+		return nil, err
+	}
+
+	summary.Held = totals.Held
+	summary.Deducted = totals.Deducted
+	summary.Released = totals.Released
+	summary.Remaining = summary.Held - summary.Deducted - summary.Released
+	return &summary, nil
+}
@@ -0,0 +1,133 @@
+package services
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"rental-mobil/models"
+	"sync"
+)
+
+// FakeGatewayName adalah nama penyedia palsu untuk pengujian lokal
+const FakeGatewayName = "fake"
+
+// FakeGateway adalah payment gateway lokal tanpa akses jaringan. Callback
+// ditandatangani dengan HMAC-SHA256 pada header X-Signature sama seperti
+// gateway sungguhan.
+type FakeGateway struct {
+	secret  []byte
+	baseURL string
+
+	mu      sync.Mutex
+	charges map[string]models.Money
+}
+
+// fakeCallback adalah isi callback yang dikirim FakeGateway
+type fakeCallback struct {
+	ChargeID  string       `json:"charge_id"`
+	Status    string       `json:"status"`
+	Method    string       `json:"method"`
+	Amount    models.Money `json:"amount"`
+	Reference string       `json:"reference"`
+}
+
+// NewFakeGateway membuat FakeGateway dengan secret penandatangan callback
+// dan baseURL untuk membentuk URL checkout
+func NewFakeGateway(secret, baseURL string) *FakeGateway {
+	return &FakeGateway{
+		secret:  []byte(secret),
+		baseURL: baseURL,
+		charges: map[string]models.Money{},
+	}
+}
+
+func (g *FakeGateway) Name() string {
+	return FakeGatewayName
+}
+
+func (g *FakeGateway) CreateCharge(req ChargeRequest) (*Charge, error) {
+	if req.Amount <= 0 {
+		return nil, errors.New("charge amount must be greater than zero")
+	}
+
+	id := "fake_" + randomHex(8)
+	g.mu.Lock()
+	g.charges[id] = req.Amount
+	g.mu.Unlock()
+
+	return &Charge{
+		ExternalID:  id,
+		CheckoutURL: g.baseURL + "/fake-gateway/charges/" + id + "/pay",
+	}, nil
+}
+
+func (g *FakeGateway) ParseCallback(body []byte, header http.Header) (*CallbackEvent, error) {
+	signature, err := hex.DecodeString(header.Get("X-Signature"))
+	if err != nil || !hmac.Equal(signature, g.sign(body)) {
+		return nil, ErrInvalidSignature
+	}
+
+	var callback fakeCallback
+	if err := json.Unmarshal(body, &callback); err != nil {
+		return nil, err
+	}
+
+	return &CallbackEvent{
+		ExternalID: callback.ChargeID,
+		Status:     callback.Status,
+		Method:     callback.Method,
+		Amount:     callback.Amount,
+		Reference:  callback.Reference,
+	}, nil
+}
+
+func (g *FakeGateway) Refund(externalID string, amount models.Money) (*RefundResult, error) {
+	if amount <= 0 {
+		return nil, errors.New("refund amount must be greater than zero")
+	}
+	return &RefundResult{Reference: "fake_refund_" + randomHex(8)}, nil
+}
+
+// SimulatePayment membuat callback "paid" bertanda tangan untuk tagihan
+// yang pernah dibuat, seolah-olah pelanggan sudah membayar via QRIS
+func (g *FakeGateway) SimulatePayment(externalID string) ([]byte, http.Header, error) {
+	g.mu.Lock()
+	amount, ok := g.charges[externalID]
+	g.mu.Unlock()
+	if !ok {
+		return nil, nil, errors.New("charge not found")
+	}
+
+	body, err := json.Marshal(fakeCallback{
+		ChargeID:  externalID,
+		Status:    models.ChargeStatusPaid,
+		Method:    models.PaymentMethodQRIS,
+		Amount:    amount,
+		Reference: "fake_txn_" + randomHex(8),
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	header := http.Header{}
+	header.Set("X-Signature", hex.EncodeToString(g.sign(body)))
+	return body, header, nil
+}
+
+// sign menghasilkan HMAC-SHA256 dari body callback
+func (g *FakeGateway) sign(body []byte) []byte {
+	mac := hmac.New(sha256.New, g.secret)
+	mac.Write(body)
+	return mac.Sum(nil)
+}
+
+// randomHex menghasilkan string hex acak sepanjang n byte
+func randomHex(n int) string {
+	b := make([]byte, n)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
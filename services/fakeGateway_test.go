@@ -0,0 +1,63 @@
+package services
+
+import (
+	"bytes"
+	"encoding/hex"
+	"net/http"
+	"rental-mobil/models"
+	"testing"
+)
+
+func TestFakeGatewayParseCallback(t *testing.T) {
+	gateway := NewFakeGateway("secret", "http://localhost:8080")
+	charge, err := gateway.CreateCharge(ChargeRequest{BookingID: 1, Amount: 150000})
+	if err != nil {
+		t.Fatal(err)
+	}
+	body, header, err := gateway.SimulatePayment(charge.ExternalID)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tampered := bytes.Replace(body, []byte("150000"), []byte("999999"), 1)
+
+	tests := []struct {
+		name    string
+		body    []byte
+		header  http.Header
+		wantErr error
+	}{
+		{name: "valid signature", body: body, header: header},
+		{name: "tampered body", body: tampered, header: header, wantErr: ErrInvalidSignature},
+		{name: "missing header", body: body, header: http.Header{}, wantErr: ErrInvalidSignature},
+		{name: "wrong secret", body: body, header: signedWith("other", body), wantErr: ErrInvalidSignature},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			event, err := gateway.ParseCallback(tt.body, tt.header)
+			if err != tt.wantErr {
+				t.Fatalf("expected error %v, got %v", tt.wantErr, err)
+			}
+			if tt.wantErr != nil {
+				return
+			}
+			if event.ExternalID != charge.ExternalID {
+				t.Errorf("expected external id %q, got %q", charge.ExternalID, event.ExternalID)
+			}
+			if event.Status != models.ChargeStatusPaid {
+				t.Errorf("expected status %q, got %q", models.ChargeStatusPaid, event.Status)
+			}
+			if event.Amount != 150000 {
+				t.Errorf("expected amount 150000, got %d", event.Amount)
+			}
+		})
+	}
+}
+
+// signedWith menandatangani body dengan secret lain untuk menguji penolakan
+func signedWith(secret string, body []byte) http.Header {
+	header := http.Header{}
+	header.Set("X-Signature", hex.EncodeToString(NewFakeGateway(secret, "").sign(body)))
+	return header
+}
@@ -0,0 +1,274 @@
+package services
+
+import (
+	"database/sql"
+	"fmt"
+	"rental-mobil/config"
+	"rental-mobil/models"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// SaveBookingInvoice membuat invoice booking jika belum ada lalu mengganti
+// baris sewa, biaya tambahan, diskon dan supir sesuai rincian harga. Nomor
+// invoice dan baris denda keterlambatan tetap dipertahankan.
+func SaveBookingInvoice(tx *sqlx.Tx, bookingID int, price *PriceBreakdown) error {
+	invoiceID, err := ensureInvoice(tx, bookingID, price.TaxPercentage)
+	if err != nil {
+		return err
+	}
+
+	deleteQuery := `DELETE FROM invoice_items WHERE invoice_id = $1 AND kind <> $2`
+	if _, err := tx.Exec(deleteQuery, invoiceID, models.InvoiceItemLateFee); err != nil {
+		return err
+	}
+
+	items := []models.InvoiceItem{
+		{Kind: models.InvoiceItemRent, Description: "Sewa mobil", Quantity: price.Days, UnitPrice: price.DailyRent, Amount: price.BaseRent},
+	}
+	if price.Surcharge != 0 {
+		items = append(items, models.InvoiceItem{Kind: models.InvoiceItemSurcharge, Description: fmt.Sprintf("Biaya tambahan jenis booking %g%%", price.SurchargePercentage), Quantity: 1, UnitPrice: price.Surcharge, Amount: price.Surcharge})
+	}
+	if price.DiscountAmount != 0 {
+		items = append(items, models.InvoiceItem{Kind: models.InvoiceItemDiscount, Description: fmt.Sprintf("Diskon membership %g%%", price.DiscountPercentage), Quantity: 1, UnitPrice: -price.DiscountAmount, Amount: -price.DiscountAmount})
+	}
+	if price.TotalDriverCost != 0 {
+		items = append(items, models.InvoiceItem{Kind: models.InvoiceItemDriver, Description: "Biaya supir", Quantity: price.Days, UnitPrice: price.DriverDailyCost, Amount: price.TotalDriverCost})
+	}
+
+	for _, item := range items {
+		if err := insertInvoiceItem(tx, invoiceID, item); err != nil {
+			return err
+		}
+	}
+
+	_, err = tx.Exec(`UPDATE invoices SET tax_percentage = $1 WHERE id = $2`, price.TaxPercentage, invoiceID)
+	if err != nil {
+		return err
+	}
+	return refreshInvoiceTotals(tx, invoiceID)
+}
+
+// SetInvoiceLateFee mengganti baris denda keterlambatan pada invoice booking
+func SetInvoiceLateFee(tx *sqlx.Tx, bookingID int, lateDays int, dailyRate, lateFee models.Money) error {
+	invoiceID, err := bookingInvoiceID(tx, bookingID)
+	if err != nil {
+		return err
+	}
+
+	deleteQuery := `DELETE FROM invoice_items WHERE invoice_id = $1 AND kind = $2`
+	if _, err := tx.Exec(deleteQuery, invoiceID, models.InvoiceItemLateFee); err != nil {
+		return err
+	}
+
+	if lateFee != 0 {
+		item := models.InvoiceItem{
+			Kind:        models.InvoiceItemLateFee,
+			Description: fmt.Sprintf("Denda keterlambatan %d hari", lateDays),
+			Quantity:    lateDays,
+			UnitPrice:   dailyRate.Multiply(config.GetLateFeeMultiplier()),
+			Amount:      lateFee,
+		}
+		if err := insertInvoiceItem(tx, invoiceID, item); err != nil {
+			return err
+		}
+	}
+
+	return refreshInvoiceTotals(tx, invoiceID)
+}
+
+// SetInvoiceCancellationFee mengganti seluruh baris invoice booking yang
+// dibatalkan dengan satu baris biaya pembatalan, lalu mengembalikan total
+// invoice yaitu biaya pembatalan termasuk pajaknya
+func SetInvoiceCancellationFee(tx *sqlx.Tx, bookingID int, fee models.Money) (models.Money, error) {
+	invoiceID, err := bookingInvoiceID(tx, bookingID)
+	if err != nil {
+		return 0, err
+	}
+
+	if _, err := tx.Exec(`DELETE FROM invoice_items WHERE invoice_id = $1`, invoiceID); err != nil {
+		return 0, err
+	}
+
+	if fee != 0 {
+		item := models.InvoiceItem{
+			Kind:        models.InvoiceItemCancellationFee,
+			Description: "Biaya pembatalan",
+			Quantity:    1,
+			UnitPrice:   fee,
+			Amount:      fee,
+		}
+		if err := insertInvoiceItem(tx, invoiceID, item); err != nil {
+			return 0, err
+		}
+	}
+
+	if err := refreshInvoiceTotals(tx, invoiceID); err != nil {
+		return 0, err
+	}
+
+	var total models.Money
+	err = tx.Get(&total, `SELECT total FROM invoices WHERE id = $1`, invoiceID)
+	return total, err
+}
+
+// SaveStoredBookingInvoice membuat invoice untuk booking lama yang belum
+// memilikinya dari nominal yang tersimpan di booking, bukan dari tarif saat
+// ini. Booking lama ditagih tanpa pajak, dan booking yang dibatalkan hanya
+// berisi biaya pembatalannya.
+func SaveStoredBookingInvoice(tx *sqlx.Tx, booking *models.Booking) error {
+	startRent, err := ParseRentDate(booking.StartRent)
+	if err != nil {
+		return err
+	}
+	endRent, err := ParseRentDate(booking.EndRent)
+	if err != nil {
+		return err
+	}
+	days := int(endRent.Sub(startRent).Hours() / 24)
+
+	lateDays := 0
+	if booking.ReturnDate != nil {
+		if returned, err := ParseRentDate(*booking.ReturnDate); err == nil && returned.After(endRent) {
+			lateDays = int(returned.Sub(endRent).Hours() / 24)
+		}
+	}
+
+	invoiceID, err := ensureInvoice(tx, booking.ID, 0)
+	if err != nil {
+		return err
+	}
+
+	items := []models.InvoiceItem{}
+	if booking.Status == models.BookingStatusCancelled {
+		if booking.CancellationFee != 0 {
+			items = append(items, models.InvoiceItem{Kind: models.InvoiceItemCancellationFee, Description: "Biaya pembatalan", Quantity: 1, UnitPrice: booking.CancellationFee, Amount: booking.CancellationFee})
+		}
+	} else {
+		items = append(items, models.InvoiceItem{Kind: models.InvoiceItemRent, Description: fmt.Sprintf("Sewa mobil %d hari", days), Quantity: 1, UnitPrice: booking.TotalCost, Amount: booking.TotalCost})
+		if booking.TotalDriverCost != 0 {
+			items = append(items, models.InvoiceItem{Kind: models.InvoiceItemDriver, Description: fmt.Sprintf("Biaya supir %d hari", days), Quantity: 1, UnitPrice: booking.TotalDriverCost, Amount: booking.TotalDriverCost})
+		}
+		if booking.LateFee != 0 {
+			items = append(items, models.InvoiceItem{Kind: models.InvoiceItemLateFee, Description: fmt.Sprintf("Denda keterlambatan %d hari", lateDays), Quantity: 1, UnitPrice: booking.LateFee, Amount: booking.LateFee})
+		}
+	}
+
+	for _, item := range items {
+		if err := insertInvoiceItem(tx, invoiceID, item); err != nil {
+			return err
+		}
+	}
+	return refreshInvoiceTotals(tx, invoiceID)
+}
+
+// GetBookingInvoice mengambil invoice booking beserta seluruh barisnya
+func GetBookingInvoice(q sqlx.Queryer, bookingID int) (*models.Invoice, error) {
+	var invoice models.Invoice
+	query := `SELECT id, booking_id, number, issued_at, tax_percentage, subtotal, tax, total
+        FROM invoices WHERE booking_id = $1`
+	if err := sqlx.Get(q, &invoice, query, bookingID); err != nil {
+		return nil, err
+	}
+
+	invoice.Items = []models.InvoiceItem{}
+	itemsQuery := `SELECT id, invoice_id, kind, description, quantity, unit_price, amount
+        FROM invoice_items WHERE invoice_id = $1 ORDER BY id`
+	if err := sqlx.Select(q, &invoice.Items, itemsQuery, invoice.ID); err != nil {
+		return nil, err
+	}
+
+	return &invoice, nil
+}
+
+// bookingInvoiceID mengembalikan id invoice booking. Booking lama yang belum
+// memiliki invoice dibuatkan dulu dari nominal tersimpan agar baris denda atau
+// biaya pembatalan tidak menjadi satu-satunya tagihan.
+func bookingInvoiceID(tx *sqlx.Tx, bookingID int) (int, error) {
+	var invoiceID int
+	err := tx.Get(&invoiceID, `SELECT id FROM invoices WHERE booking_id = $1 FOR UPDATE`, bookingID)
+	if err != sql.ErrNoRows {
+		return invoiceID, err
+	}
+
+	var booking models.Booking
+	query := `SELECT id, start_rent, end_rent, total_cost, total_driver_cost, status, late_fee, return_date, cancellation_fee
+        FROM bookings WHERE id = $1`
+	if err := tx.Get(&booking, query, bookingID); err != nil {
+		return 0, err
+	}
+	if err := SaveStoredBookingInvoice(tx, &booking); err != nil {
+		return 0, err
+	}
+
+	err = tx.Get(&invoiceID, `SELECT id FROM invoices WHERE booking_id = $1`, bookingID)
+	return invoiceID, err
+}
+
+// ensureInvoice mengembalikan id invoice booking, membuat invoice bernomor
+// baru jika belum ada. Nomor diturunkan dari id sehingga selalu naik, tetapi
+// bisa bercelah karena id SERIAL yang terpakai oleh transaksi gagal tidak
+// dipakai ulang.
+func ensureInvoice(tx *sqlx.Tx, bookingID int, taxPercentage float64) (int, error) {
+	var invoiceID int
+	err := tx.Get(&invoiceID, `SELECT id FROM invoices WHERE booking_id = $1 FOR UPDATE`, bookingID)
+	if err != sql.ErrNoRows {
+		return invoiceID, err
+	}
+
+	insertQuery := `INSERT INTO invoices (booking_id, number, issued_at, tax_percentage, subtotal, tax, total)
+        VALUES ($1, '', NOW(), $2, 0, 0, 0) RETURNING id`
+	if err := tx.Get(&invoiceID, insertQuery, bookingID, taxPercentage); err != nil {
+		return 0, err
+	}
+
+	numberQuery := `UPDATE invoices SET number = 'INV-' || to_char(issued_at, 'YYYYMM') || '-' || lpad(id::text, 6, '0') WHERE id = $1`
+	if _, err := tx.Exec(numberQuery, invoiceID); err != nil {
+		return 0, err
+	}
+
+	return invoiceID, nil
+}
+
+// insertInvoiceItem menyimpan satu baris invoice
+func insertInvoiceItem(tx *sqlx.Tx, invoiceID int, item models.InvoiceItem) error {
+	query := `INSERT INTO invoice_items (invoice_id, kind, description, quantity, unit_price, amount)
+        VALUES ($1, $2, $3, $4, $5, $6)`
+	_, err := tx.Exec(query, invoiceID, item.Kind, item.Description, item.Quantity, item.UnitPrice, item.Amount)
+	return err
+}
+
+// refreshInvoiceTotals menghitung ulang subtotal, baris pajak dan total invoice
+func refreshInvoiceTotals(tx *sqlx.Tx, invoiceID int) error {
+	deleteQuery := `DELETE FROM invoice_items WHERE invoice_id = $1 AND kind = $2`
+	if _, err := tx.Exec(deleteQuery, invoiceID, models.InvoiceItemTax); err != nil {
+		return err
+	}
+
+	var taxPercentage float64
+	if err := tx.Get(&taxPercentage, `SELECT tax_percentage FROM invoices WHERE id = $1`, invoiceID); err != nil {
+		return err
+	}
+
+	var subtotal models.Money
+	sumQuery := `SELECT COALESCE(SUM(amount), 0) FROM invoice_items WHERE invoice_id = $1`
+	if err := tx.Get(&subtotal, sumQuery, invoiceID); err != nil {
+		return err
+	}
+
+	tax := subtotal.Percent(taxPercentage)
+	item := models.InvoiceItem{
+		Kind:        models.InvoiceItemTax,
+		Description: fmt.Sprintf("Pajak %g%%", taxPercentage),
+		Quantity:    1,
+		UnitPrice:   tax,
+		Amount:      tax,
+	}
+	if err := insertInvoiceItem(tx, invoiceID, item); err != nil {
+		return err
+	}
+
+	updateQuery := `UPDATE invoices SET subtotal = $1, tax = $2, total = $3 WHERE id = $4`
+	_, err := tx.Exec(updateQuery, subtotal, tax, subtotal+tax, invoiceID)
+	return err
+}
@@ -0,0 +1,143 @@
+package services
+
+import (
+	"bytes"
+	"fmt"
+	"html/template"
+	"rental-mobil/models"
+	"strings"
+)
+
+// InvoiceView adalah data yang ditampilkan pada invoice cetak
+type InvoiceView struct {
+	Invoice      *models.Invoice
+	CustomerName string
+	CarName      string
+	StartRent    string
+	EndRent      string
+}
+
+// LineItems mengembalikan baris invoice tanpa baris pajak karena pajak sudah
+// dicetak tersendiri di bawah subtotal
+func (v InvoiceView) LineItems() []models.InvoiceItem {
+	items := []models.InvoiceItem{}
+	for _, item := range v.Invoice.Items {
+		if item.Kind != models.InvoiceItemTax {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+var invoiceHTMLTemplate = template.Must(template.New("invoice").Parse(`<!DOCTYPE html>
+<html lang="id">
+<head>
+<meta charset="utf-8">
+<title>Invoice {{.Invoice.Number}}</title>
+<style>
+body { font-family: Arial, sans-serif; margin: 40px; color: #222; }
+h1 { margin-bottom: 0; }
+table { width: 100%; border-collapse: collapse; margin-top: 24px; }
+th, td { padding: 8px; border-bottom: 1px solid #ddd; text-align: left; }
+td.num, th.num { text-align: right; }
+tfoot td { font-weight: bold; }
+@media print { body { margin: 0; } }
+</style>
+</head>
+<body>
+<h1>Invoice</h1>
+<p>No. {{.Invoice.Number}}<br>Tanggal {{.Invoice.IssuedAt.Format "02-01-2006"}}</p>
+<p>Pelanggan: {{.CustomerName}}<br>Mobil: {{.CarName}}<br>Periode sewa: {{.StartRent}} s/d {{.EndRent}}</p>
+<table>
+<thead><tr><th>Keterangan</th><th class="num">Jumlah</th><th class="num">Harga</th><th class="num">Total</th></tr></thead>
+<tbody>
+{{range .LineItems}}<tr><td>{{.Description}}</td><td class="num">{{.Quantity}}</td><td class="num">{{.UnitPrice}}</td><td class="num">{{.Amount}}</td></tr>
+{{end}}</tbody>
+<tfoot>
+<tr><td colspan="3">Subtotal</td><td class="num">{{.Invoice.Subtotal}}</td></tr>
+<tr><td colspan="3">Pajak</td><td class="num">{{.Invoice.Tax}}</td></tr>
+<tr><td colspan="3">Total</td><td class="num">{{.Invoice.Total}}</td></tr>
+</tfoot>
+</table>
+</body>
+</html>
+`))
+
+// RenderInvoiceHTML menghasilkan invoice dalam bentuk HTML siap cetak
+func RenderInvoiceHTML(view InvoiceView) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := invoiceHTMLTemplate.Execute(&buf, view); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// RenderInvoicePDF menghasilkan invoice sebagai PDF satu halaman A4 dengan
+// huruf Courier sehingga kolom bisa disejajarkan dengan spasi
+func RenderInvoicePDF(view InvoiceView) []byte {
+	invoice := view.Invoice
+	lines := []string{
+		"INVOICE",
+		"",
+		"No.          : " + invoice.Number,
+		"Tanggal      : " + invoice.IssuedAt.Format("02-01-2006"),
+		"Pelanggan    : " + view.CustomerName,
+		"Mobil        : " + view.CarName,
+		"Periode sewa : " + view.StartRent + " s/d " + view.EndRent,
+		"",
+		fmt.Sprintf("%-36s %6s %16s %16s", "Keterangan", "Jumlah", "Harga", "Total"),
+		strings.Repeat("-", 77),
+	}
+	for _, item := range view.LineItems() {
+		lines = append(lines, fmt.Sprintf("%-36.36s %6d %16s %16s", item.Description, item.Quantity, item.UnitPrice, item.Amount))
+	}
+	lines = append(lines,
+		strings.Repeat("-", 77),
+		fmt.Sprintf("%-60s %16s", "Subtotal", invoice.Subtotal),
+		fmt.Sprintf("%-60s %16s", "Pajak", invoice.Tax),
+		fmt.Sprintf("%-60s %16s", "Total", invoice.Total),
+	)
+
+	return buildTextPDF(lines)
+}
+
+// buildTextPDF menyusun dokumen PDF minimal berisi baris-baris teks
+func buildTextPDF(lines []string) []byte {
+	var content bytes.Buffer
+	content.WriteString("BT\n/F1 9 Tf\n12 TL\n40 800 Td\n")
+	for _, line := range lines {
+		content.WriteString("(" + escapePDFText(line) + ") Tj T*\n")
+	}
+	content.WriteString("ET\n")
+
+	objects := []string{
+		"<< /Type /Catalog /Pages 2 0 R >>",
+		"<< /Type /Pages /Kids [3 0 R] /Count 1 >>",
+		"<< /Type /Page /Parent 2 0 R /MediaBox [0 0 595 842] /Resources << /Font << /F1 5 0 R >> >> /Contents 4 0 R >>",
+		fmt.Sprintf("<< /Length %d >>\nstream\n%sendstream", content.Len(), content.String()),
+		"<< /Type /Font /Subtype /Type1 /BaseFont /Courier /Encoding /WinAnsiEncoding >>",
+	}
+
+	var pdf bytes.Buffer
+	pdf.WriteString("%PDF-1.4\n")
+	offsets := make([]int, len(objects))
+	for i, object := range objects {
+		offsets[i] = pdf.Len()
+		fmt.Fprintf(&pdf, "%d 0 obj\n%s\nendobj\n", i+1, object)
+	}
+
+	xref := pdf.Len()
+	fmt.Fprintf(&pdf, "xref\n0 %d\n0000000000 65535 f \n", len(objects)+1)
+	for _, offset := range offsets {
+		fmt.Fprintf(&pdf, "%010d 00000 n \n", offset)
+	}
+	fmt.Fprintf(&pdf, "trailer\n<< /Size %d /Root 1 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(objects)+1, xref)
+
+	return pdf.Bytes()
+}
+
+// escapePDFText meng-escape karakter khusus string PDF
+func escapePDFText(text string) string {
+	replacer := strings.NewReplacer(`\`, `\\`, "(", `\(`, ")", `\)`)
+	return replacer.Replace(text)
+}
@@ -0,0 +1,41 @@
+package services
+
+import (
+	"rental-mobil/models"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRenderInvoiceHTMLPrintsTaxOnce(t *testing.T) {
+	view := InvoiceView{
+		Invoice: &models.Invoice{
+			Number:        "INV-202601-000001",
+			IssuedAt:      time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC),
+			TaxPercentage: 11,
+			Subtotal:      900000,
+			Tax:           99000,
+			Total:         999000,
+			Items: []models.InvoiceItem{
+				{Kind: models.InvoiceItemRent, Description: "Sewa mobil", Quantity: 3, UnitPrice: 300000, Amount: 900000},
+				{Kind: models.InvoiceItemTax, Description: "Pajak 11%", Quantity: 1, UnitPrice: 99000, Amount: 99000},
+			},
+		},
+	}
+
+	html, err := RenderInvoiceHTML(view)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(html), "Pajak 11%") {
+		t.Error("tax row must not be printed in the item table")
+	}
+	if count := strings.Count(string(html), "Rp 99.000"); count != 1 {
+		t.Errorf("expected tax amount once, got %d", count)
+	}
+
+	pdf := string(RenderInvoicePDF(view))
+	if strings.Contains(pdf, "Pajak 11%") {
+		t.Error("tax row must not be printed in the PDF item table")
+	}
+}
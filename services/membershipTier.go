@@ -0,0 +1,132 @@
+package services
+
+import (
+	"log"
+	"rental-mobil/models"
+	"time"
+)
+
+// CustomerRentalStats merangkum booking selesai pelanggan dalam 12 bulan terakhir
+type CustomerRentalStats struct {
+	CustomerID   int          `json:"customer_id" db:"customer_id"`
+	MembershipID *int         `json:"membership_id" db:"membership_id"`
+	Rentals      int          `json:"rentals" db:"rentals"`
+	Spend        models.Money `json:"spend" db:"spend"`
+	Days         int          `json:"days" db:"days"`
+}
+
+// TierChange menjelaskan perubahan membership satu pelanggan
+type TierChange struct {
+	CustomerRentalStats
+	NewMembershipID *int `json:"new_membership_id"`
+}
+
+// MembershipTierSource menyediakan daftar tingkat membership, terurut dari
+// diskon tertinggi
+type MembershipTierSource interface {
+	ListTiers() ([]models.Membership, error)
+}
+
+// CustomerTierStore menyediakan statistik sewa pelanggan dan menyimpan
+// perubahan membershipnya
+type CustomerTierStore interface {
+	RentalStats(since string) ([]CustomerRentalStats, error)
+	ChangeMembership(customerID int, membershipID *int, effectiveDate string) error
+}
+
+// hasThresholds true jika tingkat memiliki minimal satu syarat. Tingkat tanpa
+// syarat dianggap diatur manual sehingga tidak ikut evaluasi otomatis.
+func hasThresholds(tier models.Membership) bool {
+	return tier.MinRentals > 0 || tier.MinSpend > 0 || tier.MinDays > 0
+}
+
+// qualifies true jika statistik pelanggan memenuhi semua syarat tingkat
+func qualifies(stats CustomerRentalStats, tier models.Membership) bool {
+	return stats.Rentals >= tier.MinRentals && stats.Spend >= tier.MinSpend && stats.Days >= tier.MinDays
+}
+
+// EvaluateMembershipTiers menghitung tingkat membership yang pantas untuk
+// setiap pelanggan berdasarkan booking yang sudah dikembalikan selama 12
+// bulan terakhir. Tingkat dengan diskon tertinggi yang syaratnya terpenuhi
+// akan dipilih; jika tidak ada, membership pelanggan dicabut. Tingkat tanpa
+// syarat dilewati dan pelanggan yang memegangnya tidak diubah. Jika dryRun
+// true, perubahan hanya dilaporkan tanpa disimpan.
+func EvaluateMembershipTiers(memberships MembershipTierSource, customers CustomerTierStore, dryRun bool) ([]TierChange, error) {
+	allTiers, err := memberships.ListTiers()
+	if err != nil {
+		return nil, err
+	}
+
+	tiers := []models.Membership{}
+	manual := map[int]bool{}
+	for _, tier := range allTiers {
+		if hasThresholds(tier) {
+			tiers = append(tiers, tier)
+		} else {
+			manual[tier.ID] = true
+		}
+	}
+
+	since := time.Now().AddDate(-1, 0, 0).Format("2006-01-02")
+	stats, err := customers.RentalStats(since)
+	if err != nil {
+		return nil, err
+	}
+
+	changes := []TierChange{}
+	today := time.Now().Format("2006-01-02")
+	for _, s := range stats {
+		if s.MembershipID != nil && manual[*s.MembershipID] {
+			continue
+		}
+
+		var target *int
+		for _, tier := range tiers {
+			if qualifies(s, tier) {
+				id := tier.ID
+				target = &id
+				break
+			}
+		}
+
+		if target == nil && s.MembershipID == nil {
+			continue
+		}
+		if target != nil && s.MembershipID != nil && *target == *s.MembershipID {
+			continue
+		}
+
+		changes = append(changes, TierChange{CustomerRentalStats: s, NewMembershipID: target})
+		if dryRun {
+			continue
+		}
+
+		if err := customers.ChangeMembership(s.CustomerID, target, today); err != nil {
+			return changes, err
+		}
+	}
+
+	return changes, nil
+}
+
+// StartMembershipTierScheduler menjalankan evaluasi tingkat membership secara
+// berkala di goroutine terpisah. Interval 0 menonaktifkan penjadwalan.
+func StartMembershipTierScheduler(memberships MembershipTierSource, customers CustomerTierStore, interval time.Duration) {
+	if interval <= 0 {
+		return
+	}
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for range ticker.C {
+			changes, err := EvaluateMembershipTiers(memberships, customers, false)
+			if err != nil {
+				log.Printf("Failed to evaluate membership tiers: %v", err)
+				continue
+			}
+			log.Printf("Membership tiers evaluated, %d customers changed", len(changes))
+		}
+	}()
+}
@@ -0,0 +1,40 @@
+package services
+
+import (
+	"database/sql"
+	"rental-mobil/models"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// BookingBalance merangkum tagihan dan pembayaran sebuah booking
+type BookingBalance struct {
+	Total      models.Money `json:"total"`       // Total tagihan termasuk pajak dan denda
+	AmountPaid models.Money `json:"amount_paid"` // Pembayaran + uang muka - refund
+	BalanceDue models.Money `json:"balance_due"` // Sisa yang harus dibayar
+}
+
+// GetBookingBalance menghitung total tagihan, jumlah dibayar dan sisa tagihan
+// booking. Total diambil dari invoice; booking tanpa invoice memakai total
+// biaya yang tersimpan di booking.
+func GetBookingBalance(q sqlx.Queryer, bookingID int) (*BookingBalance, error) {
+	var balance BookingBalance
+
+	err := sqlx.Get(q, &balance.Total, `SELECT total FROM invoices WHERE booking_id = $1`, bookingID)
+	if err == sql.ErrNoRows {
+		fallbackQuery := `SELECT total_cost + total_driver_cost + late_fee FROM bookings WHERE id = $1`
+		err = sqlx.Get(q, &balance.Total, fallbackQuery, bookingID)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	paidQuery := `SELECT COALESCE(SUM(CASE WHEN type = $2 THEN -amount ELSE amount END), 0)
+        FROM payments WHERE booking_id = $1`
+	if err := sqlx.Get(q, &balance.AmountPaid, paidQuery, bookingID, models.PaymentTypeRefund); err != nil {
+		return nil, err
+	}
+
+	balance.BalanceDue = balance.Total - balance.AmountPaid
+	return &balance, nil
+}
@@ -0,0 +1,71 @@
+package services
+
+import (
+	"errors"
+	"net/http"
+	"rental-mobil/models"
+	"sync"
+)
+
+// ErrInvalidSignature dikembalikan gateway jika tanda tangan callback tidak cocok
+var ErrInvalidSignature = errors.New("invalid callback signature")
+
+// ChargeRequest adalah data untuk membuat tagihan online
+type ChargeRequest struct {
+	BookingID   int
+	Amount      models.Money
+	Description string
+}
+
+// Charge adalah tagihan yang berhasil dibuat di payment gateway
+type Charge struct {
+	ExternalID  string
+	CheckoutURL string
+}
+
+// CallbackEvent adalah notifikasi status tagihan dari payment gateway
+type CallbackEvent struct {
+	ExternalID string
+	Status     string       // salah satu models.ChargeStatus*
+	Method     string       // metode yang dipakai pelanggan, contoh qris
+	Amount     models.Money // nominal yang benar-benar dibayar
+	Reference  string       // nomor referensi transaksi di gateway
+}
+
+// RefundResult adalah hasil refund di payment gateway
+type RefundResult struct {
+	Reference string
+}
+
+// PaymentGateway adalah kontrak yang harus dipenuhi setiap penyedia
+// pembayaran online
+type PaymentGateway interface {
+	// Name mengembalikan nama unik penyedia, dipakai pada URL webhook
+	Name() string
+	// CreateCharge membuat tagihan baru dan mengembalikan URL pembayarannya
+	CreateCharge(req ChargeRequest) (*Charge, error)
+	// ParseCallback memverifikasi tanda tangan lalu membaca isi callback
+	ParseCallback(body []byte, header http.Header) (*CallbackEvent, error)
+	// Refund mengembalikan sebagian atau seluruh dana sebuah tagihan
+	Refund(externalID string, amount models.Money) (*RefundResult, error)
+}
+
+var (
+	gatewaysMu sync.RWMutex
+	gateways   = map[string]PaymentGateway{}
+)
+
+// RegisterPaymentGateway mendaftarkan penyedia pembayaran online
+func RegisterPaymentGateway(gateway PaymentGateway) {
+	gatewaysMu.Lock()
+	defer gatewaysMu.Unlock()
+	gateways[gateway.Name()] = gateway
+}
+
+// GetPaymentGateway mengambil penyedia pembayaran berdasarkan nama
+func GetPaymentGateway(name string) (PaymentGateway, bool) {
+	gatewaysMu.RLock()
+	defer gatewaysMu.RUnlock()
+	gateway, ok := gateways[name]
+	return gateway, ok
+}
@@ -0,0 +1,128 @@
+package services
+
+import (
+	"rental-mobil/config"
+	"rental-mobil/models"
+	"time"
+)
+
+// PricingError adalah kesalahan input yang membuat harga booking tidak bisa
+// dihitung, misalnya format tanggal salah atau durasi di bawah minimum
+type PricingError string
+
+func (e PricingError) Error() string {
+	return string(e)
+}
+
+// PriceBreakdown adalah rincian harga sebuah booking
+type PriceBreakdown struct {
+	Days                int          `json:"days"`
+	DailyRent           models.Money `json:"daily_rent"`
+	BaseRent            models.Money `json:"base_rent"`            // Sewa harian x durasi
+	SurchargePercentage float64      `json:"surcharge_percentage"` // Dari jenis booking
+	Surcharge           models.Money `json:"surcharge"`
+	DiscountPercentage  float64      `json:"discount_percentage"` // Dari membership pelanggan
+	DiscountAmount      models.Money `json:"discount_amount"`
+	TotalCost           models.Money `json:"total_cost"` // Sewa mobil setelah biaya tambahan dan diskon
+	DriverDailyCost     models.Money `json:"driver_daily_cost"`
+	TotalDriverCost     models.Money `json:"total_driver_cost"`
+	Subtotal            models.Money `json:"subtotal"` // Sewa mobil + biaya supir
+	TaxPercentage       float64      `json:"tax_percentage"`
+	Tax                 models.Money `json:"tax"`
+	GrandTotal          models.Money `json:"grand_total"`
+	Deposit             models.Money `json:"deposit"` // Uang jaminan mobil, tidak termasuk GrandTotal
+}
+
+// PricingSource menyediakan data yang dibutuhkan untuk menghitung harga booking
+type PricingSource interface {
+	BookingType(id int) (*models.BookingType, error)
+	Car(id int) (*models.Car, error)
+	// MembershipDiscount mengembalikan diskon membership pelanggan, 0 jika
+	// pelanggan tidak memiliki membership
+	MembershipDiscount(customerID int) (float64, error)
+	Driver(id int) (*models.Driver, error)
+}
+
+// CalculateBookingPrice menghitung harga booking dari mobil, jenis booking,
+// membership pelanggan dan supir. Dipakai oleh pembuatan maupun perubahan
+// booking agar aturan harganya selalu sama.
+func CalculateBookingPrice(source PricingSource, booking *models.Booking) (*PriceBreakdown, error) {
+	startRent, err := time.Parse("2006-01-02", booking.StartRent)
+	if err != nil {
+		return nil, PricingError("Invalid start rent date format")
+	}
+	endRent, err := time.Parse("2006-01-02", booking.EndRent)
+	if err != nil {
+		return nil, PricingError("Invalid end rent date format")
+	}
+
+	// Hitung durasi sewa (dalam hari)
+	days := int(endRent.Sub(startRent).Hours() / 24)
+	if days <= 0 {
+		return nil, PricingError("End rent date must be after start rent date")
+	}
+
+	// Jenis booking menentukan kebutuhan supir, durasi minimum dan biaya tambahan
+	bookingType, err := source.BookingType(booking.BookingTypeID)
+	if err != nil {
+		return nil, err
+	}
+	if bookingType.RequiresDriver && booking.DriverID == nil {
+		return nil, PricingError("Driver is required for this booking type")
+	}
+	if !bookingType.RequiresDriver && booking.DriverID != nil {
+		return nil, PricingError("This booking type does not include a driver")
+	}
+	if days < bookingType.MinDays {
+		return nil, PricingError("Rental duration is shorter than the minimum for this booking type")
+	}
+
+	car, err := source.Car(booking.CarID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Customer tanpa membership membayar harga penuh tanpa diskon
+	discount, err := source.MembershipDiscount(booking.CustomerID)
+	if err != nil {
+		return nil, err
+	}
+
+	price := &PriceBreakdown{
+		Days:                days,
+		DailyRent:           car.DailyRent,
+		BaseRent:            car.DailyRent.Times(days),
+		SurchargePercentage: bookingType.SurchargePercentage,
+		DiscountPercentage:  discount,
+		Deposit:             car.DepositAmount,
+	}
+	// Setiap komponen dibulatkan ke rupiah terdekat sebelum dijumlahkan
+	price.Surcharge = price.BaseRent.Percent(bookingType.SurchargePercentage)
+	price.DiscountAmount = (price.BaseRent + price.Surcharge).Percent(discount)
+	price.TotalCost = price.BaseRent + price.Surcharge - price.DiscountAmount
+
+	// Biaya supir hanya dihitung jika booking memakai supir
+	if booking.DriverID != nil {
+		driver, err := source.Driver(*booking.DriverID)
+		if err != nil {
+			return nil, err
+		}
+		price.DriverDailyCost = driver.DailyCost
+		price.TotalDriverCost = price.DriverDailyCost.Times(days)
+	}
+
+	price.Subtotal = price.TotalCost + price.TotalDriverCost
+	price.TaxPercentage = config.GetTaxPercentage()
+	price.Tax = price.Subtotal.Percent(price.TaxPercentage)
+	price.GrandTotal = price.Subtotal + price.Tax
+	return price, nil
+}
+
+// ParseRentDate menerima tanggal sewa dalam format YYYY-MM-DD maupun
+// timestamp lengkap seperti yang dikembalikan kolom DATE oleh driver postgres
+func ParseRentDate(value string) (time.Time, error) {
+	if len(value) > 10 {
+		value = value[:10]
+	}
+	return time.Parse("2006-01-02", value)
+}